@@ -0,0 +1,63 @@
+package kraph
+
+import "sort"
+
+func (g *graph) GreedyIndependentSet() []ID {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	ids := g.unsafeNodeIDs()
+	neighbors := make(map[ID]map[ID]float64, len(ids))
+	for _, id := range ids {
+		neighbors[id] = g.unsafeNeighborWeights(id)
+	}
+
+	// 度数相同时按 id 排序，保证结果稳定
+	sort.SliceStable(ids, func(i, j int) bool {
+		return len(neighbors[ids[i]]) < len(neighbors[ids[j]])
+	})
+
+	excluded := make(map[ID]bool, len(ids))
+	set := make([]ID, 0)
+	for _, id := range ids {
+		if excluded[id] {
+			continue
+		}
+
+		if _, ok := neighbors[id][id]; ok {
+			continue
+		}
+
+		set = append(set, id)
+		for nid := range neighbors[id] {
+			excluded[nid] = true
+		}
+	}
+	sortIDs(set)
+
+	return set
+}
+
+func (g *graph) VertexCover() []ID {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	covered := make(map[ID]bool)
+	cover := make([]ID, 0)
+	// 依次取出一条未被覆盖的边，将两个端点都加入覆盖集
+	for _, e := range g.unsafeEdges() {
+		if covered[e.From] || covered[e.To] {
+			continue
+		}
+
+		covered[e.From] = true
+		cover = append(cover, e.From)
+		if e.To != e.From {
+			covered[e.To] = true
+			cover = append(cover, e.To)
+		}
+	}
+	sortIDs(cover)
+
+	return cover
+}
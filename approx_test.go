@@ -0,0 +1,74 @@
+package kraph
+
+import (
+	"reflect"
+	"testing"
+)
+
+// 按 from -> to 的顺序构建测试用的图
+func buildGraph(nodes []string, edges [][2]string, wgts ...float64) Graph {
+	g := NewGraph()
+	for _, n := range nodes {
+		g.AddNode(NewNode(NewNid(n)))
+	}
+
+	for i, e := range edges {
+		wgt := 1.0
+		if i < len(wgts) {
+			wgt = wgts[i]
+		}
+		g.AddEdge(NewNid(e[1]), NewNid(e[0]), wgt)
+	}
+
+	return g
+}
+
+func idStrings(ids []ID) []string {
+	s := make([]string, len(ids))
+	for i, id := range ids {
+		s[i] = id.String()
+	}
+
+	return s
+}
+
+func TestGreedyIndependentSet(t *testing.T) {
+	// 星形图：中心 c 连接 a, b, d，另有孤立点 e 和带自环的 f
+	g := buildGraph(
+		[]string{"a", "b", "c", "d", "e", "f"},
+		[][2]string{{"c", "a"}, {"b", "c"}, {"c", "d"}, {"f", "f"}},
+	)
+
+	got := idStrings(g.GreedyIndependentSet())
+	want := []string{"a", "b", "d", "e"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GreedyIndependentSet() = %v, want %v", got, want)
+	}
+}
+
+func TestVertexCover(t *testing.T) {
+	g := buildGraph(
+		[]string{"a", "b", "c", "d", "e"},
+		[][2]string{{"a", "b"}, {"b", "c"}, {"c", "d"}, {"e", "e"}},
+	)
+
+	cover := g.VertexCover()
+	in := make(map[ID]bool)
+	for _, id := range cover {
+		in[id] = true
+	}
+
+	for _, id := range []string{"a", "b", "c", "d", "e"} {
+		tmap, _ := g.GetTargets(NewNid(id))
+		for tid := range tmap {
+			if !in[NewNid(id)] && !in[tid] {
+				t.Errorf("edge %s -> %s is not covered by %v", id, tid, cover)
+			}
+		}
+	}
+
+	want := []string{"a", "b", "c", "d", "e"}
+	if got := idStrings(cover); !reflect.DeepEqual(got, want) {
+		t.Errorf("VertexCover() = %v, want %v", got, want)
+	}
+}
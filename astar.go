@@ -0,0 +1,58 @@
+package kraph
+
+import "fmt"
+
+func (g *graph) AStar(from, to ID, heuristic func(ID) float64) ([]ID, float64, error) {
+	from, to = g.cfg.key(from), g.cfg.key(to)
+
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	if !g.unsafeIdExist(from) {
+		return nil, 0, fmt.Errorf("%s does not exist in graph", from)
+	}
+
+	if !g.unsafeIdExist(to) {
+		return nil, 0, fmt.Errorf("%s does not exist in graph", to)
+	}
+
+	h := func(id ID) float64 {
+		if heuristic == nil {
+			return 0
+		}
+
+		return heuristic(id)
+	}
+
+	dist := map[ID]float64{from: 0}
+	prev := make(map[ID]ID)
+
+	// 不使用关闭集合，找到更短的距离时重新加入 frontier，这样 heuristic 只需要是可采纳的，不要求一致
+	f := NewFrontier()
+	f.Push(from, h(from))
+	for {
+		cur, _, ok := f.Pop()
+		if !ok {
+			break
+		}
+
+		if cur == to {
+			return buildPath(prev, from, to), dist[to], nil
+		}
+
+		for tid, wgt := range g.nodeTargets[cur] {
+			if wgt < 0 {
+				return nil, 0, fmt.Errorf("negative weight on edge from %s to %s", cur, tid)
+			}
+
+			nd := dist[cur] + wgt
+			if d, ok := dist[tid]; !ok || nd < d {
+				dist[tid] = nd
+				prev[tid] = cur
+				f.Update(tid, nd+h(tid))
+			}
+		}
+	}
+
+	return nil, 0, fmt.Errorf("%w: no path from %s to %s", ErrUnreachable, from, to)
+}
@@ -0,0 +1,73 @@
+package kraph
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// 构建 n x n 的双向网格，node 的 id 为 "x,y"，每条边的权重为 1
+func gridGraph(n int) Graph {
+	g := NewGraph()
+	name := func(x, y int) ID { return NewNid(strconv.Itoa(x) + "," + strconv.Itoa(y)) }
+	for x := 0; x < n; x++ {
+		for y := 0; y < n; y++ {
+			g.AddNode(NewNode(name(x, y)))
+		}
+	}
+	for x := 0; x < n; x++ {
+		for y := 0; y < n; y++ {
+			if x+1 < n {
+				g.AddEdge(name(x+1, y), name(x, y), 1)
+				g.AddEdge(name(x, y), name(x+1, y), 1)
+			}
+			if y+1 < n {
+				g.AddEdge(name(x, y+1), name(x, y), 1)
+				g.AddEdge(name(x, y), name(x, y+1), 1)
+			}
+		}
+	}
+
+	return g
+}
+
+func TestAStar(t *testing.T) {
+	g := gridGraph(5)
+	from, to := NewNid("0,0"), NewNid("4,3")
+
+	// 曼哈顿距离是可采纳的
+	manhattan := func(id ID) float64 {
+		xy := strings.Split(id.String(), ",")
+		x, _ := strconv.Atoi(xy[0])
+		y, _ := strconv.Atoi(xy[1])
+
+		return math.Abs(float64(4-x)) + math.Abs(float64(3-y))
+	}
+
+	path, w, err := g.AStar(from, to, manhattan)
+	if err != nil || w != 7 || len(path) != 8 {
+		t.Errorf("AStar = %v, %v, %v", path, w, err)
+	}
+
+	_, dw, _ := g.ShortestPathAvoiding(from, to, nil)
+	if _, zw, err := g.AStar(from, to, nil); err != nil || zw != dw {
+		t.Errorf("AStar without heuristic = %v, %v; Dijkstra = %v", zw, err, dw)
+	}
+
+	chain := buildGraph(
+		[]string{"a", "b", "c", "d"},
+		[][2]string{{"a", "b"}, {"b", "d"}, {"a", "c"}, {"c", "d"}},
+		1, 5, 2, 1,
+	)
+	path, w, err = chain.AStar(NewNid("a"), NewNid("d"), func(ID) float64 { return 0 })
+	if err != nil || w != 3 || !reflect.DeepEqual(idStrings(path), []string{"a", "c", "d"}) {
+		t.Errorf("AStar(a, d) = %v, %v, %v", path, w, err)
+	}
+
+	if _, _, err := chain.AStar(NewNid("d"), NewNid("a"), nil); !errors.Is(err, ErrUnreachable) {
+		t.Errorf("expected ErrUnreachable, got %v", err)
+	}
+}
@@ -0,0 +1,137 @@
+package kraph
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+func (g *graph) EigenvectorCentrality(iterations int, tolerance float64) (map[ID]float64, error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	n := len(g.nodeList)
+	if n == 0 {
+		return nil, errors.New("graph is empty")
+	}
+
+	x := make(map[ID]float64, n)
+	for id := range g.nodeList {
+		x[id] = 1.0 / float64(n)
+	}
+
+	for i := 0; i < iterations; i++ {
+		// 使用 A + I 迭代，特征向量不变，但可以避免二分图等情况下的震荡
+		next := make(map[ID]float64, n)
+		for id, v := range x {
+			next[id] = v
+		}
+		for from, tmap := range g.nodeTargets {
+			for to, wgt := range tmap {
+				next[to] += x[from] * wgt
+			}
+		}
+
+		norm := 0.0
+		for _, v := range next {
+			norm += v * v
+		}
+		norm = math.Sqrt(norm)
+		if norm == 0 {
+			return nil, errors.New("eigenvector centrality is undefined for this graph")
+		}
+
+		diff := 0.0
+		for id := range next {
+			next[id] /= norm
+			diff += math.Abs(next[id] - x[id])
+		}
+		x = next
+
+		if diff < float64(n)*tolerance {
+			return x, nil
+		}
+	}
+
+	return nil, fmt.Errorf("eigenvector centrality failed to converge in %d iterations", iterations)
+}
+
+// Katz 中心性的收敛阈值，各 node 变化之和小于 node 数量乘以该值时视为收敛
+const katzTolerance = 1e-9
+
+// 使用幂迭代估计邻接矩阵（权重取绝对值）的谱半径
+// 迭代的是 A + I，其谱半径恰好比 A 大 1，这样可以避免在环和二分图上震荡
+func (g *graph) unsafeSpectralRadius(iterations int) float64 {
+	n := len(g.nodeList)
+	x := make(map[ID]float64, n)
+	for id := range g.nodeList {
+		x[id] = 1.0 / math.Sqrt(float64(n))
+	}
+
+	ratio := 1.0
+	for i := 0; i < iterations; i++ {
+		next := make(map[ID]float64, n)
+		for id, v := range x {
+			next[id] = v
+		}
+		for from, tmap := range g.nodeTargets {
+			for to, wgt := range tmap {
+				next[to] += x[from] * math.Abs(wgt)
+			}
+		}
+
+		norm := 0.0
+		for _, v := range next {
+			norm += v * v
+		}
+		norm = math.Sqrt(norm)
+
+		// x 的范数始终为 1，因此 norm 即为本轮的放大倍数
+		ratio = norm
+		for id := range next {
+			next[id] /= norm
+		}
+		x = next
+	}
+
+	return ratio - 1
+}
+
+func (g *graph) KatzCentrality(alpha, beta float64, iterations int) (map[ID]float64, error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	n := len(g.nodeList)
+	if n == 0 {
+		return nil, errors.New("graph is empty")
+	}
+
+	if rho := g.unsafeSpectralRadius(iterations); rho > 1e-9 && alpha >= 1/rho {
+		return nil, fmt.Errorf("alpha %v is too large, it must be smaller than 1/%v", alpha, rho)
+	}
+
+	x := make(map[ID]float64, n)
+	for i := 0; i < iterations; i++ {
+		next := make(map[ID]float64, n)
+		for id := range g.nodeList {
+			next[id] = beta
+		}
+		for from, tmap := range g.nodeTargets {
+			for to, wgt := range tmap {
+				next[to] += alpha * x[from] * wgt
+			}
+		}
+
+		diff := 0.0
+		for id, v := range next {
+			diff += math.Abs(v - x[id])
+		}
+		x = next
+
+		if diff < float64(n)*katzTolerance {
+			return x, nil
+		}
+	}
+
+	return nil, fmt.Errorf("katz centrality failed to converge in %d iterations", iterations)
+}
@@ -0,0 +1,76 @@
+package kraph
+
+import (
+	"math"
+	"testing"
+)
+
+// 按无向图的方式构建，每条边以两个方向分别加入
+func buildUndirected(nodes []string, edges [][2]string) Graph {
+	both := make([][2]string, 0, 2*len(edges))
+	for _, e := range edges {
+		both = append(both, e, [2]string{e[1], e[0]})
+	}
+
+	return buildGraph(nodes, both)
+}
+
+func TestEigenvectorCentrality(t *testing.T) {
+	// 路径 a - b - c：特征向量为 (1, √2, 1) 归一化
+	g := buildUndirected([]string{"a", "b", "c"}, [][2]string{{"a", "b"}, {"b", "c"}})
+
+	c, err := g.EigenvectorCentrality(1000, 1e-10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]float64{"a": 0.5, "b": math.Sqrt2 / 2, "c": 0.5}
+	for id, w := range want {
+		if math.Abs(c[NewNid(id)]-w) > 1e-6 {
+			t.Errorf("centrality[%s] = %v, want %v", id, c[NewNid(id)], w)
+		}
+	}
+
+	if _, err := g.EigenvectorCentrality(1, 1e-12); err == nil {
+		t.Error("expected convergence error with a single iteration")
+	}
+
+	if _, err := NewGraph().EigenvectorCentrality(10, 1e-6); err == nil {
+		t.Error("expected error for empty graph")
+	}
+}
+
+func TestKatzCentrality(t *testing.T) {
+	// 引用 DAG：a -> c, b -> c, c -> d
+	g := buildGraph(
+		[]string{"a", "b", "c", "d"},
+		[][2]string{{"a", "c"}, {"b", "c"}, {"c", "d"}},
+	)
+
+	c, err := g.KatzCentrality(0.5, 1.0, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// x_c = 1 + 0.5 * (1 + 1) = 2, x_d = 1 + 0.5 * 2 = 2
+	want := map[string]float64{"a": 1, "b": 1, "c": 2, "d": 2}
+	for id, w := range want {
+		if math.Abs(c[NewNid(id)]-w) > 1e-9 {
+			t.Errorf("katz[%s] = %v, want %v", id, c[NewNid(id)], w)
+		}
+	}
+
+	// 环 a -> b -> a 的谱半径为 1，alpha 必须小于 1
+	cyc := buildGraph([]string{"a", "b"}, [][2]string{{"a", "b"}, {"b", "a"}})
+	if _, err := cyc.KatzCentrality(1.5, 1.0, 200); err == nil {
+		t.Error("expected error for alpha larger than 1/spectral radius")
+	}
+
+	c, err = cyc.KatzCentrality(0.5, 1.0, 200)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(c[NewNid("a")]-2) > 1e-6 {
+		t.Errorf("katz on cycle = %v, want 2", c[NewNid("a")])
+	}
+}
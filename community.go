@@ -0,0 +1,340 @@
+package kraph
+
+import (
+	"math/rand"
+	"sort"
+)
+
+// 以无向图的视角返回每个 node 排好序的邻居列表，不包含自环
+func (g *graph) unsafeSortedNeighbors(ids []ID) map[ID][]ID {
+	adj := make(map[ID][]ID, len(ids))
+	for _, id := range ids {
+		ns := make([]ID, 0)
+		for nid := range g.unsafeNeighborWeights(id) {
+			if nid != id {
+				ns = append(ns, nid)
+			}
+		}
+		sortIDs(ns)
+		adj[id] = ns
+	}
+
+	return adj
+}
+
+// 按照 node id 的顺序将社区编号重新映射为从 0 开始的连续整数
+func renumberCommunities(ids []ID, labels map[ID]int) map[ID]int {
+	mapping := make(map[int]int)
+	result := make(map[ID]int, len(ids))
+	for _, id := range ids {
+		l := labels[id]
+		if _, ok := mapping[l]; !ok {
+			mapping[l] = len(mapping)
+		}
+		result[id] = mapping[l]
+	}
+
+	return result
+}
+
+func (g *graph) LabelPropagation(maxIter int, rng *rand.Rand) map[ID]int {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	if rng == nil {
+		rng = rand.New(rand.NewSource(1))
+	}
+
+	ids := g.unsafeNodeIDs()
+	adj := g.unsafeSortedNeighbors(ids)
+
+	labels := make(map[ID]int, len(ids))
+	for i, id := range ids {
+		labels[id] = i
+	}
+
+	order := make([]ID, len(ids))
+	copy(order, ids)
+
+	for iter := 0; iter < maxIter; iter++ {
+		rng.Shuffle(len(order), func(i, j int) {
+			order[i], order[j] = order[j], order[i]
+		})
+
+		changed := false
+		for _, id := range order {
+			if len(adj[id]) == 0 {
+				continue
+			}
+
+			counts := make(map[int]int)
+			best := 0
+			for _, nid := range adj[id] {
+				counts[labels[nid]]++
+				if counts[labels[nid]] > best {
+					best = counts[labels[nid]]
+				}
+			}
+
+			// 当前标签已经是最多的之一时保持不变，保证算法能够收敛
+			if counts[labels[id]] == best {
+				continue
+			}
+
+			tied := make([]int, 0)
+			for l, c := range counts {
+				if c == best {
+					tied = append(tied, l)
+				}
+			}
+			sort.Ints(tied)
+
+			labels[id] = tied[rng.Intn(len(tied))]
+			changed = true
+		}
+
+		if !changed {
+			break
+		}
+	}
+
+	return renumberCommunities(ids, labels)
+}
+
+// 以整数下标表示的无向加权图，用于社区发现
+type undirectedLevel struct {
+	// 每个 node 内部的权重，自环的权重计算两次
+	self []float64
+	// 不同 node 之间的权重，是对称的
+	adj []map[int]float64
+}
+
+func (l *undirectedLevel) degree(i int) float64 {
+	k := l.self[i]
+	for _, w := range l.adj[i] {
+		k += w
+	}
+
+	return k
+}
+
+// 构建无向视图，两个方向的边权重相加，node 按 ids 的顺序编号
+func (g *graph) unsafeUndirectedLevel(ids []ID) *undirectedLevel {
+	index := make(map[ID]int, len(ids))
+	for i, id := range ids {
+		index[id] = i
+	}
+
+	l := &undirectedLevel{
+		self: make([]float64, len(ids)),
+		adj:  make([]map[int]float64, len(ids)),
+	}
+	for i, id := range ids {
+		l.adj[i] = make(map[int]float64)
+		for nid, wgt := range g.unsafeNeighborWeights(id) {
+			if nid == id {
+				l.self[i] = 2 * wgt
+			} else {
+				l.adj[i][index[nid]] = wgt
+			}
+		}
+	}
+
+	return l
+}
+
+// 计算划分 comm 在 l 上的模块度
+func (l *undirectedLevel) modularity(comm []int) float64 {
+	m2 := 0.0
+	in := make(map[int]float64)
+	tot := make(map[int]float64)
+	for i := range l.self {
+		k := l.degree(i)
+		m2 += k
+		tot[comm[i]] += k
+		in[comm[i]] += l.self[i]
+		for j, w := range l.adj[i] {
+			if comm[j] == comm[i] {
+				in[comm[i]] += w
+			}
+		}
+	}
+
+	if m2 == 0 {
+		return 0
+	}
+
+	q := 0.0
+	for c, t := range tot {
+		q += in[c]/m2 - (t/m2)*(t/m2)
+	}
+
+	return q
+}
+
+// Louvain 的局部移动阶段，返回每个 node 所在的社区（已重新编号）以及是否有 node 发生了移动
+func (l *undirectedLevel) localMove() ([]int, bool) {
+	n := len(l.self)
+	comm := make([]int, n)
+	tot := make([]float64, n)
+	k := make([]float64, n)
+	m2 := 0.0
+	for i := 0; i < n; i++ {
+		comm[i] = i
+		k[i] = l.degree(i)
+		tot[i] = k[i]
+		m2 += k[i]
+	}
+
+	if m2 == 0 {
+		return comm, false
+	}
+
+	moved := false
+	for improved := true; improved; {
+		improved = false
+		for i := 0; i < n; i++ {
+			kin := make(map[int]float64)
+			for j, w := range l.adj[i] {
+				kin[comm[j]] += w
+			}
+
+			old := comm[i]
+			tot[old] -= k[i]
+
+			best := old
+			bestGain := kin[old] - tot[old]*k[i]/m2
+
+			cands := make([]int, 0, len(kin))
+			for c := range kin {
+				cands = append(cands, c)
+			}
+			sort.Ints(cands)
+
+			for _, c := range cands {
+				if gain := kin[c] - tot[c]*k[i]/m2; gain > bestGain+1e-12 {
+					best, bestGain = c, gain
+				}
+			}
+
+			tot[best] += k[i]
+			comm[i] = best
+			if best != old {
+				improved = true
+				moved = true
+			}
+		}
+	}
+
+	// 重新编号为连续的整数
+	mapping := make(map[int]int)
+	for i, c := range comm {
+		if _, ok := mapping[c]; !ok {
+			mapping[c] = len(mapping)
+		}
+		comm[i] = mapping[c]
+	}
+
+	return comm, moved
+}
+
+// 将同一社区的 node 合并为一个 node，得到下一层的图
+func (l *undirectedLevel) aggregate(comm []int) *undirectedLevel {
+	size := 0
+	for _, c := range comm {
+		if c+1 > size {
+			size = c + 1
+		}
+	}
+
+	nl := &undirectedLevel{
+		self: make([]float64, size),
+		adj:  make([]map[int]float64, size),
+	}
+	for c := range nl.adj {
+		nl.adj[c] = make(map[int]float64)
+	}
+
+	for i := range l.self {
+		ci := comm[i]
+		nl.self[ci] += l.self[i]
+		for j, w := range l.adj[i] {
+			if cj := comm[j]; cj == ci {
+				nl.self[ci] += w
+			} else {
+				nl.adj[ci][cj] += w
+			}
+		}
+	}
+
+	return nl
+}
+
+func (g *graph) Louvain() (map[ID]int, float64) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	ids := g.unsafeNodeIDs()
+	base := g.unsafeUndirectedLevel(ids)
+
+	// membership[i] 表示原图中第 i 个 node 当前所在的社区
+	membership := make([]int, len(ids))
+	for i := range membership {
+		membership[i] = i
+	}
+
+	level := base
+	for {
+		comm, moved := level.localMove()
+		if !moved {
+			break
+		}
+
+		for i := range membership {
+			membership[i] = comm[membership[i]]
+		}
+		level = level.aggregate(comm)
+	}
+
+	labels := make(map[ID]int, len(ids))
+	for i, id := range ids {
+		labels[id] = membership[i]
+	}
+
+	return renumberCommunities(ids, labels), base.modularity(membership)
+}
+
+func (g *graph) Modularity(partition map[ID]int) float64 {
+	if g.cfg.caseInsensitive {
+		ks := make(map[ID]int, len(partition))
+		for id, c := range partition {
+			ks[g.cfg.key(id)] = c
+		}
+		partition = ks
+	}
+
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	ids := g.unsafeNodeIDs()
+
+	// 没有被划分的 node 使用大于所有已有编号的新编号
+	next := 0
+	for _, id := range ids {
+		if c, ok := partition[id]; ok && c >= next {
+			next = c + 1
+		}
+	}
+
+	comm := make([]int, len(ids))
+	for i, id := range ids {
+		if c, ok := partition[id]; ok {
+			comm[i] = c
+		} else {
+			comm[i] = next
+			next++
+		}
+	}
+
+	return g.unsafeUndirectedLevel(ids).modularity(comm)
+}
@@ -0,0 +1,110 @@
+package kraph
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+// 两个由一条边相连的四元完全图
+func twoCliques() Graph {
+	edges := make([][2]string, 0)
+	for _, group := range [][]string{{"a", "b", "c", "d"}, {"e", "f", "g", "h"}} {
+		for i := 0; i < len(group); i++ {
+			for j := i + 1; j < len(group); j++ {
+				edges = append(edges, [2]string{group[i], group[j]})
+			}
+		}
+	}
+	edges = append(edges, [2]string{"d", "e"})
+
+	return buildGraph([]string{"a", "b", "c", "d", "e", "f", "g", "h"}, edges)
+}
+
+func TestLabelPropagation(t *testing.T) {
+	g := twoCliques()
+
+	labels := g.LabelPropagation(100, rand.New(rand.NewSource(42)))
+	if len(labels) != 8 {
+		t.Fatalf("labels = %v", labels)
+	}
+
+	for _, group := range [][]string{{"a", "b", "c", "d"}, {"e", "f", "g", "h"}} {
+		for _, id := range group[1:] {
+			if labels[NewNid(id)] != labels[NewNid(group[0])] {
+				t.Errorf("%s and %s should share a community: %v", id, group[0], labels)
+			}
+		}
+	}
+	if labels[NewNid("a")] == labels[NewNid("h")] {
+		t.Errorf("cliques should be separate communities: %v", labels)
+	}
+
+	// 相同的种子应该得到相同的结果
+	again := g.LabelPropagation(100, rand.New(rand.NewSource(42)))
+	if !reflect.DeepEqual(labels, again) {
+		t.Errorf("results differ with the same seed: %v vs %v", labels, again)
+	}
+}
+
+func TestLouvain(t *testing.T) {
+	g := twoCliques()
+
+	labels, q := g.Louvain()
+	if len(labels) != 8 {
+		t.Fatalf("labels = %v", labels)
+	}
+
+	want := map[ID]int{}
+	for _, id := range []string{"a", "b", "c", "d"} {
+		want[NewNid(id)] = 0
+	}
+	for _, id := range []string{"e", "f", "g", "h"} {
+		want[NewNid(id)] = 1
+	}
+	if !reflect.DeepEqual(labels, want) {
+		t.Errorf("Louvain() labels = %v, want %v", labels, want)
+	}
+
+	// 两个社区各有 6 条内部边，共 13 条边：Q = 2 * (6/13 - (13/26)^2)
+	wantQ := 2 * (6.0/13 - 0.25)
+	if math.Abs(q-wantQ) > 1e-9 {
+		t.Errorf("modularity = %v, want %v", q, wantQ)
+	}
+
+	if labels, q := NewGraph().Louvain(); len(labels) != 0 || q != 0 {
+		t.Errorf("empty graph: %v, %v", labels, q)
+	}
+}
+
+func TestModularity(t *testing.T) {
+	g := twoCliques()
+
+	part := map[ID]int{}
+	for _, id := range []string{"a", "b", "c", "d"} {
+		part[NewNid(id)] = 0
+	}
+	for _, id := range []string{"e", "f", "g", "h"} {
+		part[NewNid(id)] = 1
+	}
+
+	// 13 条边，每个社区内部 6 条边，度数之和为 13
+	if q, want := g.Modularity(part), 12.0/13-0.5; math.Abs(q-want) > 1e-12 {
+		t.Errorf("Modularity = %v, want %v", q, want)
+	}
+
+	labels, lq := g.Louvain()
+	if q := g.Modularity(labels); math.Abs(q-lq) > 1e-12 {
+		t.Errorf("Modularity(Louvain) = %v, Louvain reported %v", q, lq)
+	}
+
+	// e, f, g, h 没有被划分，各自作为单独的社区
+	for _, id := range []string{"e", "f", "g", "h"} {
+		delete(part, NewNid(id))
+	}
+	want := 12.0/26 - 0.25 - (16.0+9+9+9)/(26*26)
+	if q := g.Modularity(part); math.Abs(q-want) > 1e-12 {
+		t.Errorf("Modularity with singletons = %v, want %v", q, want)
+	}
+}
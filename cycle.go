@@ -0,0 +1,278 @@
+package kraph
+
+import (
+	"fmt"
+	"math/bits"
+)
+
+// 使用 Tarjan 算法计算强连通分量，只考虑 allowed 中的 node，allowed 为 nil 时考虑所有 node
+// 分量按照逆拓扑序返回，即如果存在从分量 A 到分量 B 的边，则 B 在 A 之前
+func (g *graph) unsafeSCC(ids []ID, allowed map[ID]bool) [][]ID {
+	index := make(map[ID]int)
+	low := make(map[ID]int)
+	onStack := make(map[ID]bool)
+	stack := make([]ID, 0)
+	comps := make([][]ID, 0)
+	next := 0
+
+	var strongConnect func(v ID)
+	strongConnect = func(v ID) {
+		index[v] = next
+		low[v] = next
+		next++
+		stack = append(stack, v)
+		onStack[v] = true
+
+		for w := range g.nodeTargets[v] {
+			if allowed != nil && !allowed[w] {
+				continue
+			}
+
+			if _, ok := index[w]; !ok {
+				strongConnect(w)
+				if low[w] < low[v] {
+					low[v] = low[w]
+				}
+			} else if onStack[w] && index[w] < low[v] {
+				low[v] = index[w]
+			}
+		}
+
+		if low[v] == index[v] {
+			comp := make([]ID, 0)
+			for {
+				w := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				onStack[w] = false
+				comp = append(comp, w)
+				if w == v {
+					break
+				}
+			}
+			sortIDs(comp)
+			comps = append(comps, comp)
+		}
+	}
+
+	for _, id := range ids {
+		if allowed != nil && !allowed[id] {
+			continue
+		}
+
+		if _, ok := index[id]; !ok {
+			strongConnect(id)
+		}
+	}
+
+	return comps
+}
+
+func (g *graph) AllCycles() [][]ID {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	ids := g.unsafeNodeIDs()
+
+	// 预先对下游排序，保证结果稳定
+	adj := make(map[ID][]ID, len(ids))
+	for _, id := range ids {
+		ts := make([]ID, 0, len(g.nodeTargets[id]))
+		for tid := range g.nodeTargets[id] {
+			ts = append(ts, tid)
+		}
+		sortIDs(ts)
+		adj[id] = ts
+	}
+
+	cycles := make([][]ID, 0)
+	remaining := make(map[ID]bool, len(ids))
+	for _, id := range ids {
+		remaining[id] = true
+	}
+
+	for _, s := range ids {
+		// 只在由 id 不小于 s 的 node 组成的子图中，查找 s 所在的强连通分量
+		var comp map[ID]bool
+		for _, c := range g.unsafeSCC([]ID{s}, remaining) {
+			for _, id := range c {
+				if id == s {
+					comp = make(map[ID]bool, len(c))
+					for _, cid := range c {
+						comp[cid] = true
+					}
+				}
+			}
+		}
+
+		blocked := make(map[ID]bool)
+		blockMap := make(map[ID]map[ID]bool)
+		stack := make([]ID, 0)
+
+		var unblock func(u ID)
+		unblock = func(u ID) {
+			blocked[u] = false
+			for w := range blockMap[u] {
+				delete(blockMap[u], w)
+				if blocked[w] {
+					unblock(w)
+				}
+			}
+		}
+
+		var circuit func(v ID) bool
+		circuit = func(v ID) bool {
+			found := false
+			stack = append(stack, v)
+			blocked[v] = true
+
+			for _, w := range adj[v] {
+				if !comp[w] {
+					continue
+				}
+
+				if w == s {
+					cycle := make([]ID, len(stack))
+					copy(cycle, stack)
+					cycles = append(cycles, cycle)
+					found = true
+				} else if !blocked[w] && circuit(w) {
+					found = true
+				}
+			}
+
+			if found {
+				unblock(v)
+			} else {
+				for _, w := range adj[v] {
+					if !comp[w] {
+						continue
+					}
+					if blockMap[w] == nil {
+						blockMap[w] = make(map[ID]bool)
+					}
+					blockMap[w][v] = true
+				}
+			}
+
+			stack = stack[:len(stack)-1]
+
+			return found
+		}
+
+		circuit(s)
+		delete(remaining, s)
+	}
+
+	return cycles
+}
+
+func (g *graph) Condensation() (Graph, map[ID]ID, error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	cg := newGraph(g.cfg)
+	super := make(map[ID]ID, len(g.nodeList))
+	for _, comp := range g.unsafeSCC(g.unsafeNodeIDs(), nil) {
+		// 分量内的 id 已经排好序
+		sid := comp[0]
+		cg.unsafeAddNode(NewNode(sid))
+		for _, id := range comp {
+			super[id] = sid
+		}
+	}
+
+	for from, tmap := range g.nodeTargets {
+		for to, wgt := range tmap {
+			sf, st := super[from], super[to]
+			if sf == st {
+				continue
+			}
+
+			// 分量之间的边总是相加，不受 WithEdgeAggregator 影响
+			if w, ok := cg.nodeTargets[sf][st]; ok {
+				wgt += w
+			}
+			cg.unsafeSetEdge(sf, st, wgt)
+		}
+	}
+
+	return cg, super, nil
+}
+
+func (g *graph) DescendantCounts() map[ID]int {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	comps := g.unsafeSCC(g.unsafeNodeIDs(), nil)
+	compOf := make(map[ID]int, len(g.nodeList))
+	for c, comp := range comps {
+		for _, id := range comp {
+			compOf[id] = c
+		}
+	}
+
+	// 用位图记录每个分量可以到达的分量（包括自身），分量按逆拓扑序排列，
+	// 所以处理到某个分量时，其所有下游分量都已经计算完毕
+	words := (len(comps) + 63) / 64
+	reach := make([][]uint64, len(comps))
+	counts := make(map[ID]int, len(g.nodeList))
+	for c, comp := range comps {
+		set := make([]uint64, words)
+		set[c/64] |= 1 << uint(c%64)
+		for _, id := range comp {
+			for tid := range g.nodeTargets[id] {
+				if tc := compOf[tid]; tc != c {
+					for i, w := range reach[tc] {
+						set[i] |= w
+					}
+				}
+			}
+		}
+		reach[c] = set
+
+		total := 0
+		for i, w := range set {
+			for w != 0 {
+				b := bits.TrailingZeros64(w)
+				total += len(comps[i*64+b])
+				w &= w - 1
+			}
+		}
+
+		for _, id := range comp {
+			counts[id] = total - 1
+		}
+	}
+
+	return counts
+}
+
+func (g *graph) InSameSCC(a, b ID) (bool, error) {
+	a, b = g.cfg.key(a), g.cfg.key(b)
+
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	if !g.unsafeIdExist(a) {
+		return false, fmt.Errorf("%s does not exist in graph", a)
+	}
+
+	if !g.unsafeIdExist(b) {
+		return false, fmt.Errorf("%s does not exist in graph", b)
+	}
+
+	// 多个读者可能同时持有读锁，缓存的填充需要单独加锁
+	g.sccMu.Lock()
+	defer g.sccMu.Unlock()
+
+	if g.scc == nil {
+		g.scc = make(map[ID]int, len(g.nodeList))
+		for c, comp := range g.unsafeSCC(g.unsafeNodeIDs(), nil) {
+			for _, id := range comp {
+				g.scc[id] = c
+			}
+		}
+	}
+
+	return g.scc[a] == g.scc[b], nil
+}
@@ -0,0 +1,116 @@
+package kraph
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAllCycles(t *testing.T) {
+	// a -> b -> c -> a, b -> a, c -> c, d -> a
+	g := buildGraph(
+		[]string{"a", "b", "c", "d"},
+		[][2]string{{"a", "b"}, {"b", "c"}, {"c", "a"}, {"b", "a"}, {"c", "c"}, {"d", "a"}},
+	)
+
+	got := make([][]string, 0)
+	for _, c := range g.AllCycles() {
+		got = append(got, idStrings(c))
+	}
+
+	want := [][]string{
+		{"a", "b"},
+		{"a", "b", "c"},
+		{"c"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("AllCycles() = %v, want %v", got, want)
+	}
+
+	if cycles := buildGraph([]string{"a", "b"}, [][2]string{{"a", "b"}}).AllCycles(); len(cycles) != 0 {
+		t.Errorf("acyclic graph has cycles %v", cycles)
+	}
+}
+
+func TestCondensation(t *testing.T) {
+	// {a, b} 和 {c, d} 是两个强连通分量，e 单独一个
+	g := buildGraph(
+		[]string{"a", "b", "c", "d", "e"},
+		[][2]string{{"a", "b"}, {"b", "a"}, {"c", "d"}, {"d", "c"}, {"a", "c"}, {"b", "d"}, {"d", "e"}},
+		1, 1, 1, 1, 2, 3, 4,
+	)
+
+	cg, super, err := g.Condensation()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantSuper := map[string]string{"a": "a", "b": "a", "c": "c", "d": "c", "e": "e"}
+	for id, s := range wantSuper {
+		if super[NewNid(id)] != NewNid(s) {
+			t.Errorf("super[%s] = %v, want %s", id, super[NewNid(id)], s)
+		}
+	}
+
+	if cg.GetNodeCount() != 3 {
+		t.Errorf("node count = %d", cg.GetNodeCount())
+	}
+	if w, err := cg.GetWeight(NewNid("c"), NewNid("a")); err != nil || w != 5 {
+		t.Errorf("edge a -> c = %v, %v; want 5", w, err)
+	}
+	if w, err := cg.GetWeight(NewNid("e"), NewNid("c")); err != nil || w != 4 {
+		t.Errorf("edge c -> e = %v, %v; want 4", w, err)
+	}
+	if cycles := cg.AllCycles(); len(cycles) != 0 {
+		t.Errorf("condensation has cycles %v", cycles)
+	}
+}
+
+func TestDescendantCounts(t *testing.T) {
+	// {a, b} 是强连通分量，a -> c -> d，b -> e，f 孤立，g -> g 自环
+	g := buildGraph(
+		[]string{"a", "b", "c", "d", "e", "f", "g"},
+		[][2]string{{"a", "b"}, {"b", "a"}, {"a", "c"}, {"c", "d"}, {"b", "e"}, {"g", "g"}},
+	)
+
+	want := map[string]int{"a": 4, "b": 4, "c": 1, "d": 0, "e": 0, "f": 0, "g": 0}
+	got := make(map[string]int)
+	for id, n := range g.DescendantCounts() {
+		got[id.String()] = n
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DescendantCounts() = %v, want %v", got, want)
+	}
+}
+
+func TestInSameSCC(t *testing.T) {
+	g := buildGraph(
+		[]string{"a", "b", "c", "d"},
+		[][2]string{{"a", "b"}, {"b", "a"}, {"b", "c"}, {"c", "d"}},
+	)
+	a, b, c, d := NewNid("a"), NewNid("b"), NewNid("c"), NewNid("d")
+
+	check := func(x, y ID, want bool) {
+		t.Helper()
+		if got, err := g.InSameSCC(x, y); err != nil || got != want {
+			t.Errorf("InSameSCC(%s, %s) = %v, %v; want %v", x, y, got, err, want)
+		}
+	}
+
+	check(a, b, true)
+	check(b, c, false)
+	check(c, c, true)
+
+	// 添加 d -> b 后 b、c、d 合并到同一个分量，缓存需要失效
+	g.AddEdge(b, d, 1)
+	check(a, d, true)
+
+	// 删除 c -> d 后 c 不再能回到 b
+	g.DeleteEdge(d, c)
+	check(a, d, false)
+	check(a, c, false)
+	check(b, a, true)
+
+	if _, err := g.InSameSCC(a, NewNid("x")); err == nil {
+		t.Error("expected error for missing node")
+	}
+}
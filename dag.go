@@ -0,0 +1,267 @@
+package kraph
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// ErrCycle 表示只适用于有向无环图的操作遇到了环
+var ErrCycle = errors.New("graph contains a cycle")
+
+// 使用 Kahn 算法计算拓扑序，入度相同时按 id 顺序处理，图中存在环时返回 ErrCycle
+func (g *graph) unsafeTopoSort() ([]ID, error) {
+	ids := g.unsafeNodeIDs()
+	indeg := make(map[ID]int, len(ids))
+	queue := make([]ID, 0)
+	for _, id := range ids {
+		indeg[id] = len(g.nodeSources[id])
+		if indeg[id] == 0 {
+			queue = append(queue, id)
+		}
+	}
+
+	order := make([]ID, 0, len(ids))
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		order = append(order, cur)
+
+		next := make([]ID, 0, len(g.nodeTargets[cur]))
+		for tid := range g.nodeTargets[cur] {
+			indeg[tid]--
+			if indeg[tid] == 0 {
+				next = append(next, tid)
+			}
+		}
+		sortIDs(next)
+		queue = append(queue, next...)
+	}
+
+	if len(order) != len(ids) {
+		return nil, ErrCycle
+	}
+
+	return order, nil
+}
+
+func (g *graph) IsDAG() bool {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	return !g.unsafeHasCycle()
+}
+
+func (g *graph) WouldCreateCycle(from, to ID) (bool, error) {
+	from, to = g.cfg.key(from), g.cfg.key(to)
+
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	if !g.unsafeIdExist(from) {
+		return false, fmt.Errorf("%s does not exist in graph", from)
+	}
+
+	if !g.unsafeIdExist(to) {
+		return false, fmt.Errorf("%s does not exist in graph", to)
+	}
+
+	return g.unsafeReaches(to, from), nil
+}
+
+func (g *graph) AddEdgeIfAcyclic(from, to ID, wgt float64) error {
+	from, to = g.cfg.key(from), g.cfg.key(to)
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if err := g.unsafeCheckFrozen(); err != nil {
+		return err
+	}
+
+	if !g.unsafeIdExist(from) {
+		return fmt.Errorf("%s does not exist in graph", from)
+	}
+
+	if !g.unsafeIdExist(to) {
+		return fmt.Errorf("%s does not exist in graph", to)
+	}
+
+	if g.unsafeReaches(to, from) {
+		return fmt.Errorf("%w: edge %s -> %s would close a cycle", ErrCycle, from, to)
+	}
+
+	return g.unsafeAddEdgeChecked(from, to, wgt)
+}
+
+// 沿下游方向 DFS 判断 from 能否到达 to，找到 to 时立即返回，from 与 to 相同时返回 true
+func (g *graph) unsafeReaches(from, to ID) bool {
+	seen := map[ID]bool{from: true}
+	stack := []ID{from}
+	for len(stack) > 0 {
+		cur := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if cur == to {
+			return true
+		}
+
+		for tid := range g.nodeTargets[cur] {
+			if !seen[tid] {
+				seen[tid] = true
+				stack = append(stack, tid)
+			}
+		}
+	}
+
+	return false
+}
+
+// 使用迭代的三色 DFS 检测环，遇到指向栈中 node 的回边时立即返回 true
+func (g *graph) unsafeHasCycle() bool {
+	const (
+		white = iota
+		gray
+		black
+	)
+
+	type frame struct {
+		id      ID
+		targets []ID
+	}
+
+	color := make(map[ID]int, len(g.nodeList))
+	for root := range g.nodeList {
+		if color[root] != white {
+			continue
+		}
+
+		push := func(id ID) frame {
+			color[id] = gray
+			ts := make([]ID, 0, len(g.nodeTargets[id]))
+			for tid := range g.nodeTargets[id] {
+				ts = append(ts, tid)
+			}
+
+			return frame{id: id, targets: ts}
+		}
+
+		stack := []frame{push(root)}
+		for len(stack) > 0 {
+			top := &stack[len(stack)-1]
+			if len(top.targets) == 0 {
+				color[top.id] = black
+				stack = stack[:len(stack)-1]
+				continue
+			}
+
+			next := top.targets[len(top.targets)-1]
+			top.targets = top.targets[:len(top.targets)-1]
+			switch color[next] {
+			case gray:
+				return true
+			case white:
+				stack = append(stack, push(next))
+			}
+		}
+	}
+
+	return false
+}
+
+func (g *graph) MaxWeightPath(from, to ID) ([]ID, float64, error) {
+	from, to = g.cfg.key(from), g.cfg.key(to)
+
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	if !g.unsafeIdExist(from) {
+		return nil, 0, fmt.Errorf("%s does not exist in graph", from)
+	}
+
+	if !g.unsafeIdExist(to) {
+		return nil, 0, fmt.Errorf("%s does not exist in graph", to)
+	}
+
+	order, err := g.unsafeTopoSort()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	dist := map[ID]float64{from: 0}
+	prev := make(map[ID]ID)
+	for _, cur := range order {
+		d, ok := dist[cur]
+		if !ok {
+			continue
+		}
+
+		for tid, wgt := range g.nodeTargets[cur] {
+			if td, ok := dist[tid]; !ok || d+wgt > td {
+				dist[tid] = d + wgt
+				prev[tid] = cur
+			}
+		}
+	}
+
+	d, ok := dist[to]
+	if !ok {
+		return nil, 0, fmt.Errorf("%w: no path from %s to %s", ErrUnreachable, from, to)
+	}
+
+	return buildPath(prev, from, to), d, nil
+}
+
+func (g *graph) CountPaths(from, to ID) (int64, error) {
+	from, to = g.cfg.key(from), g.cfg.key(to)
+
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	if !g.unsafeIdExist(from) {
+		return 0, fmt.Errorf("%s does not exist in graph", from)
+	}
+
+	if !g.unsafeIdExist(to) {
+		return 0, fmt.Errorf("%s does not exist in graph", to)
+	}
+
+	memo := make(map[ID]int64)
+	// 正在访问中的 node，再次遇到说明存在环
+	visiting := make(map[ID]bool)
+
+	var count func(v ID) (int64, error)
+	count = func(v ID) (int64, error) {
+		if c, ok := memo[v]; ok {
+			return c, nil
+		}
+
+		if visiting[v] {
+			return 0, fmt.Errorf("%w: %s is on a cycle", ErrCycle, v)
+		}
+		visiting[v] = true
+
+		var total int64
+		if v == to {
+			total = 1
+		}
+
+		for tid := range g.nodeTargets[v] {
+			c, err := count(tid)
+			if err != nil {
+				return 0, err
+			}
+
+			if total > math.MaxInt64-c {
+				return 0, fmt.Errorf("number of paths from %s to %s overflows int64", from, to)
+			}
+			total += c
+		}
+
+		visiting[v] = false
+		memo[v] = total
+
+		return total, nil
+	}
+
+	return count(from)
+}
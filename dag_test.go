@@ -0,0 +1,144 @@
+package kraph
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestMaxWeightPath(t *testing.T) {
+	// a -> b -> d 权重 1 + 10，a -> c -> d 权重 5 + 5，a -> d 权重 3
+	g := buildGraph(
+		[]string{"a", "b", "c", "d", "e"},
+		[][2]string{{"a", "b"}, {"b", "d"}, {"a", "c"}, {"c", "d"}, {"a", "d"}},
+		1, 10, 5, 5, 3,
+	)
+
+	path, w, err := g.MaxWeightPath(NewNid("a"), NewNid("d"))
+	if err != nil || w != 11 || !reflect.DeepEqual(idStrings(path), []string{"a", "b", "d"}) {
+		t.Errorf("MaxWeightPath(a, d) = %v, %v, %v", path, w, err)
+	}
+
+	if _, _, err := g.MaxWeightPath(NewNid("a"), NewNid("e")); !errors.Is(err, ErrUnreachable) {
+		t.Errorf("expected ErrUnreachable, got %v", err)
+	}
+
+	g.AddEdge(NewNid("a"), NewNid("d"), 1)
+	if _, _, err := g.MaxWeightPath(NewNid("a"), NewNid("d")); !errors.Is(err, ErrCycle) {
+		t.Errorf("expected ErrCycle, got %v", err)
+	}
+}
+
+func TestCountPaths(t *testing.T) {
+	// 菱形 a -> {b, c} -> d -> e
+	g := buildGraph(
+		[]string{"a", "b", "c", "d", "e"},
+		[][2]string{{"a", "b"}, {"a", "c"}, {"b", "d"}, {"c", "d"}, {"d", "e"}, {"a", "e"}},
+	)
+
+	cases := []struct {
+		from, to string
+		want     int64
+	}{
+		{"a", "e", 3},
+		{"a", "d", 2},
+		{"e", "a", 0},
+		{"b", "b", 1},
+	}
+	for _, cs := range cases {
+		if n, err := g.CountPaths(NewNid(cs.from), NewNid(cs.to)); err != nil || n != cs.want {
+			t.Errorf("CountPaths(%s, %s) = %v, %v; want %v", cs.from, cs.to, n, err, cs.want)
+		}
+	}
+
+	g.AddEdge(NewNid("a"), NewNid("e"), 1)
+	if _, err := g.CountPaths(NewNid("a"), NewNid("e")); !errors.Is(err, ErrCycle) {
+		t.Errorf("expected ErrCycle, got %v", err)
+	}
+}
+
+func TestCountPathsOverflow(t *testing.T) {
+	// 64 个串联的菱形共有 2^64 条路径
+	nodes := []string{"n0"}
+	edges := make([][2]string, 0)
+	for i := 0; i < 64; i++ {
+		cur, up, down, next := "n"+strconv.Itoa(i), "u"+strconv.Itoa(i), "d"+strconv.Itoa(i), "n"+strconv.Itoa(i+1)
+		nodes = append(nodes, up, down, next)
+		edges = append(edges, [2]string{cur, up}, [2]string{cur, down}, [2]string{up, next}, [2]string{down, next})
+	}
+	g := buildGraph(nodes, edges)
+
+	if n, err := g.CountPaths(NewNid("n0"), NewNid("n62")); err != nil || n != 1<<62 {
+		t.Errorf("CountPaths(n0, n62) = %v, %v", n, err)
+	}
+	if _, err := g.CountPaths(NewNid("n0"), NewNid("n64")); err == nil {
+		t.Error("expected overflow error")
+	}
+}
+
+func TestIsDAG(t *testing.T) {
+	cases := []struct {
+		name  string
+		edges [][2]string
+		want  bool
+	}{
+		{"empty", nil, true},
+		{"diamond", [][2]string{{"a", "b"}, {"a", "c"}, {"b", "d"}, {"c", "d"}}, true},
+		{"cycle", [][2]string{{"a", "b"}, {"b", "c"}, {"c", "a"}}, false},
+		{"self loop", [][2]string{{"a", "b"}, {"d", "d"}}, false},
+	}
+
+	for _, c := range cases {
+		g := buildGraph([]string{"a", "b", "c", "d"}, c.edges)
+		if got := g.IsDAG(); got != c.want {
+			t.Errorf("%s: IsDAG() = %v, want %v", c.name, got, c.want)
+		}
+	}
+}
+
+func TestWouldCreateCycle(t *testing.T) {
+	// a -> b -> c，d 独立
+	g := buildGraph(
+		[]string{"a", "b", "c", "d"},
+		[][2]string{{"a", "b"}, {"b", "c"}},
+	)
+	a, b, c, d := NewNid("a"), NewNid("b"), NewNid("c"), NewNid("d")
+
+	cases := []struct {
+		from, to ID
+		want     bool
+	}{
+		{c, a, true},
+		{b, a, true},
+		{a, c, false},
+		{c, d, false},
+		{d, d, true},
+	}
+	for _, cs := range cases {
+		if got, err := g.WouldCreateCycle(cs.from, cs.to); err != nil || got != cs.want {
+			t.Errorf("WouldCreateCycle(%s, %s) = %v, %v; want %v", cs.from, cs.to, got, err, cs.want)
+		}
+	}
+
+	if _, err := g.WouldCreateCycle(a, NewNid("x")); err == nil {
+		t.Error("expected error for missing node")
+	}
+
+	if err := g.AddEdgeIfAcyclic(c, a, 1); !errors.Is(err, ErrCycle) {
+		t.Errorf("AddEdgeIfAcyclic(c, a) = %v, want ErrCycle", err)
+	}
+	if _, ok := g.TargetWeight(c, a); ok {
+		t.Error("rejected edge should not be added")
+	}
+
+	if err := g.AddEdgeIfAcyclic(a, c, 2); err != nil {
+		t.Fatal(err)
+	}
+	if w, _ := g.EdgeWeight(a, c); w != 2 {
+		t.Errorf("EdgeWeight(a, c) = %v, want 2", w)
+	}
+	if !g.IsDAG() {
+		t.Error("graph should stay acyclic")
+	}
+}
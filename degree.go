@@ -0,0 +1,72 @@
+package kraph
+
+import "math"
+
+func (g *graph) DegreeAssortativity() float64 {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	// 无向视图中每个 node 的邻居数量
+	deg := make(map[ID]float64, len(g.nodeList))
+	for id := range g.nodeList {
+		n := 0
+		for nid := range g.unsafeNeighborWeights(id) {
+			if nid != id {
+				n++
+			}
+		}
+		deg[id] = float64(n)
+	}
+
+	// 每条无向边 {u, v} 同时计入 (du, dv) 和 (dv, du)，因此两端的均值和方差相同
+	var n, sum, sumSq, sumProd float64
+	for id := range g.nodeList {
+		for nid := range g.unsafeNeighborWeights(id) {
+			if nid == id {
+				continue
+			}
+
+			du, dv := deg[id], deg[nid]
+			n++
+			sum += du
+			sumSq += du * du
+			sumProd += du * dv
+		}
+	}
+
+	if n == 0 {
+		return math.NaN()
+	}
+
+	mean := sum / n
+	variance := sumSq/n - mean*mean
+	if variance <= 0 {
+		return math.NaN()
+	}
+
+	return (sumProd/n - mean*mean) / variance
+}
+
+func (g *graph) AverageDegree() float64 {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	if len(g.nodeList) == 0 {
+		return 0
+	}
+
+	// 每条边（包括自环）为度数之和贡献 2
+	return 2 * float64(g.st.edges) / float64(len(g.nodeList))
+}
+
+func (g *graph) DegreeDistribution() map[int]int {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	dist := make(map[int]int, len(g.st.degreeCount))
+	for d, n := range g.st.degreeCount {
+		dist[d] = n
+	}
+
+	return dist
+}
@@ -0,0 +1,62 @@
+package kraph
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestDegreeAssortativity(t *testing.T) {
+	// 星形图：中心只与叶子相连，完全异配
+	star := buildGraph(
+		[]string{"c", "a", "b", "d", "e"},
+		[][2]string{{"c", "a"}, {"b", "c"}, {"c", "d"}, {"e", "c"}},
+	)
+	if r := star.DegreeAssortativity(); math.Abs(r+1) > 1e-12 {
+		t.Errorf("star assortativity = %v, want -1", r)
+	}
+
+	// 两个三角形之间以 c -> d 相连，度数 [2 2 3 3 2 2]
+	g := buildGraph(
+		[]string{"a", "b", "c", "d", "e", "f"},
+		[][2]string{{"a", "b"}, {"b", "c"}, {"c", "a"}, {"c", "d"}, {"d", "e"}, {"e", "f"}, {"f", "d"}, {"b", "a"}},
+	)
+	// 7 条无向边共 14 个有序端点对：(2,2)x4, (2,3)x4, (3,2)x4, (3,3)x2，相关系数为 -1/6
+	if r := g.DegreeAssortativity(); math.Abs(r+1.0/6) > 1e-12 {
+		t.Errorf("assortativity = %v, want -1/6", r)
+	}
+
+	if r := buildGraph([]string{"a", "b"}, [][2]string{{"a", "b"}}).DegreeAssortativity(); !math.IsNaN(r) {
+		t.Errorf("single edge assortativity = %v, want NaN", r)
+	}
+	if r := NewGraph().DegreeAssortativity(); !math.IsNaN(r) {
+		t.Errorf("empty graph assortativity = %v, want NaN", r)
+	}
+}
+
+func TestAverageDegreeAndDistribution(t *testing.T) {
+	// 度数：a 3（含自环 2），b 2，c 1，d 0
+	g := buildGraph(
+		[]string{"a", "b", "c", "d"},
+		[][2]string{{"a", "b"}, {"b", "c"}, {"a", "a"}},
+	)
+
+	if got := g.AverageDegree(); got != 1.5 {
+		t.Errorf("AverageDegree() = %v, want 1.5", got)
+	}
+
+	want := map[int]int{3: 1, 2: 1, 1: 1, 0: 1}
+	if got := g.DegreeDistribution(); !reflect.DeepEqual(got, want) {
+		t.Errorf("DegreeDistribution() = %v, want %v", got, want)
+	}
+
+	g.DeleteNode(NewNid("b"))
+	want = map[int]int{2: 1, 0: 2}
+	if got := g.DegreeDistribution(); !reflect.DeepEqual(got, want) {
+		t.Errorf("DegreeDistribution() after delete = %v, want %v", got, want)
+	}
+
+	if got := NewGraph().AverageDegree(); got != 0 {
+		t.Errorf("empty graph AverageDegree() = %v, want 0", got)
+	}
+}
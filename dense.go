@@ -0,0 +1,997 @@
+package kraph
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+// 使用邻接矩阵存储的图，node 被映射为连续的行列号，所有边的权重保存在同一个 []float64 中
+// 适用于 node 数量在几百以内的小而稠密的图，查询和遍历边时不需要经过嵌套的 map
+type denseGraph struct {
+	mu  sync.RWMutex
+	cfg config
+
+	// id -> 行列号，ids[i] 和 nodes[i] 为行列号 i 上的 node，已删除的位置为 nil 并记录在 free 中以便复用
+	index map[ID]int
+	ids   []ID
+	nodes []Node
+	free  []int
+
+	// n 为矩阵的边长，w[i*n+j] 为第 i 个 node 指向第 j 个 node 的边的权重，has[i*n+j] 表示这条边是否存在
+	n   int
+	w   []float64
+	has []bool
+
+	// 每个行列号的入度和出度
+	in, out []int
+
+	edges        int
+	addEdgeCalls uint64
+	deleteCalls  uint64
+
+	// WithInsertionOrder 时按添加顺序记录的边，否则为 nil
+	order *edgeOrder
+
+	frozen bool
+}
+
+// NewDenseGraph 创建一个使用邻接矩阵存储的图，适用于 node 数量在几百以内的小而稠密的图
+// capacity 为预先分配的矩阵边长，node 数量超过 capacity 时矩阵会按两倍扩容，矩阵占用 O(capacity²) 的内存
+// opts 与 NewGraph 相同，WithIncrementalConnectivity 会被忽略
+// node、边、权重、度数的查询和修改直接在矩阵上进行，其余算法在调用时根据矩阵构建一份临时的邻接表后运行，
+// 其中会修改图的方法（例如 NormalizeOutWeights、SplitNode、RemoveAndBypass）在成功后把结果写回矩阵，
+// FilterNodes、Union 等返回新图的方法返回的是 NewGraph 创建的普通图
+func NewDenseGraph(capacity int, opts ...Option) Graph {
+	cfg := config{}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	if capacity < 1 {
+		capacity = 1
+	}
+
+	d := &denseGraph{
+		cfg:   cfg,
+		index: make(map[ID]int, capacity),
+		n:     capacity,
+		w:     make([]float64, capacity*capacity),
+		has:   make([]bool, capacity*capacity),
+	}
+	if cfg.insertionOrder {
+		d.order = newEdgeOrder()
+	}
+
+	return d
+}
+
+// 将矩阵的边长扩大为 n，已有的边保持在原来的行列号上
+func (d *denseGraph) unsafeGrow(n int) {
+	w := make([]float64, n*n)
+	has := make([]bool, n*n)
+	for i := range d.ids {
+		copy(w[i*n:], d.w[i*d.n:i*d.n+len(d.ids)])
+		copy(has[i*n:], d.has[i*d.n:i*d.n+len(d.ids)])
+	}
+	d.n, d.w, d.has = n, w, has
+}
+
+// 清空所有的 node 和边，矩阵的内存会被保留，调用次数的累计值保持不变
+func (d *denseGraph) unsafeReset() {
+	for i := range d.ids {
+		for k := i * d.n; k < i*d.n+len(d.ids); k++ {
+			d.w[k] = 0
+			d.has[k] = false
+		}
+	}
+
+	d.index = make(map[ID]int, d.n)
+	d.ids = d.ids[:0]
+	d.nodes = d.nodes[:0]
+	d.free = nil
+	d.in = d.in[:0]
+	d.out = d.out[:0]
+	d.edges = 0
+	if d.order != nil {
+		d.order = newEdgeOrder()
+	}
+}
+
+// graph 已经冻结时返回 ErrFrozen，设置了 WithFrozenPanic 时直接 panic，调用方需要持有写锁
+func (d *denseGraph) unsafeCheckFrozen() error {
+	if !d.frozen {
+		return nil
+	}
+
+	if d.cfg.panicOnFrozen {
+		panic(ErrFrozen)
+	}
+
+	return ErrFrozen
+}
+
+// 返回 id 的行列号，node 不存在时返回 error
+func (d *denseGraph) unsafeSlot(id ID) (int, error) {
+	i, ok := d.index[id]
+	if !ok {
+		return 0, fmt.Errorf("%s does not exist in graph", id)
+	}
+
+	return i, nil
+}
+
+// 判断 node 数量是否已经达到 WithMaxNodes 设置的上限
+func (d *denseGraph) unsafeFull() bool {
+	return d.cfg.maxNodes > 0 && len(d.index) >= d.cfg.maxNodes
+}
+
+// 为 node 分配行列号，优先复用已删除 node 的位置，不够时扩容，调用方需要持有写锁并保证 node 不存在
+func (d *denseGraph) unsafeAddNode(nd Node) {
+	id := d.cfg.key(nd.GetId())
+	if len(d.free) > 0 {
+		i := d.free[len(d.free)-1]
+		d.free = d.free[:len(d.free)-1]
+		d.index[id], d.ids[i], d.nodes[i] = i, id, nd
+
+		return
+	}
+
+	i := len(d.ids)
+	if i >= d.n {
+		d.unsafeGrow(2 * d.n)
+	}
+	d.index[id] = i
+	d.ids = append(d.ids, id)
+	d.nodes = append(d.nodes, nd)
+	d.in = append(d.in, 0)
+	d.out = append(d.out, 0)
+}
+
+// 直接写入第 i 个 node 指向第 j 个 node 的权重，不记录添加顺序，调用方需要持有写锁
+func (d *denseGraph) unsafePut(i, j int, wgt float64) {
+	k := i*d.n + j
+	if !d.has[k] {
+		d.has[k] = true
+		d.out[i]++
+		d.in[j]++
+		d.edges++
+	}
+	d.w[k] = wgt
+}
+
+// 写入第 i 个 node 指向第 j 个 node 的权重，边不存在时创建，调用方需要持有写锁
+func (d *denseGraph) unsafeSetEdge(i, j int, wgt float64) {
+	if d.order != nil && !d.has[i*d.n+j] {
+		d.order.add(d.ids[i], d.ids[j])
+	}
+	d.unsafePut(i, j, wgt)
+}
+
+// 删除第 i 个 node 指向第 j 个 node 的边，如果边不存在则返回 false，调用方需要持有写锁
+func (d *denseGraph) unsafeRemoveEdge(i, j int) bool {
+	k := i*d.n + j
+	if !d.has[k] {
+		return false
+	}
+
+	d.has[k] = false
+	d.w[k] = 0
+	d.out[i]--
+	d.in[j]--
+	d.edges--
+	if d.order != nil {
+		d.order.remove(d.ids[i], d.ids[j])
+	}
+
+	return true
+}
+
+func (d *denseGraph) unsafeDeleteNode(id ID) ([]Edge, bool) {
+	d.deleteCalls++
+
+	i, ok := d.index[id]
+	if !ok {
+		return nil, false
+	}
+
+	removed := make([]Edge, 0, d.out[i]+d.in[i])
+	for j, other := range d.ids {
+		if d.has[i*d.n+j] {
+			removed = append(removed, Edge{From: id, To: other, Weight: d.w[i*d.n+j]})
+			d.unsafeRemoveEdge(i, j)
+		}
+
+		// 自环已经在下游中删除了
+		if d.has[j*d.n+i] {
+			removed = append(removed, Edge{From: other, To: id, Weight: d.w[j*d.n+i]})
+			d.unsafeRemoveEdge(j, i)
+		}
+	}
+
+	delete(d.index, id)
+	d.ids[i], d.nodes[i] = nil, nil
+	d.free = append(d.free, i)
+
+	sortEdges(removed)
+
+	return removed, true
+}
+
+// 根据矩阵构建一份邻接表形式的临时 graph，用于运行还没有基于矩阵实现的算法，调用方需要持有锁
+func (d *denseGraph) unsafeView() *graph {
+	cfg := d.cfg
+	cfg.insertionOrder, cfg.connectivity = false, false
+
+	v := newGraph(cfg)
+	for _, nd := range d.nodes {
+		if nd != nil {
+			v.unsafeAddNode(nd)
+		}
+	}
+
+	for i, from := range d.ids {
+		if from == nil {
+			continue
+		}
+
+		for j, to := range d.ids {
+			if d.has[i*d.n+j] {
+				v.unsafeSetEdge(from, to, d.w[i*d.n+j])
+			}
+		}
+	}
+
+	if d.order != nil {
+		v.order = d.order.clone()
+	}
+
+	return v
+}
+
+// 在读锁内构建临时 graph，之后对它的读取不需要再持有 d 的锁
+func (d *denseGraph) view() *graph {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	return d.unsafeView()
+}
+
+// 用 v 的 node 和边替换矩阵中的内容，不修改添加顺序，调用方需要持有写锁
+func (d *denseGraph) unsafeLoad(v *graph) {
+	order := d.order
+	d.unsafeReset()
+	d.order = order
+
+	for _, nd := range v.nodeList {
+		d.unsafeAddNode(nd)
+	}
+
+	for from, tmap := range v.nodeTargets {
+		for to, wgt := range tmap {
+			d.unsafePut(d.index[from], d.index[to], wgt)
+		}
+	}
+}
+
+// 在写锁内基于临时 graph 运行 fn，fn 成功时把结果写回矩阵，失败时不做任何修改
+func (d *denseGraph) modify(fn func(v *graph) error) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if err := d.unsafeCheckFrozen(); err != nil {
+		return err
+	}
+
+	v := d.unsafeView()
+	if err := fn(v); err != nil {
+		return err
+	}
+
+	d.unsafeLoad(v)
+	if d.order != nil {
+		d.order = v.order
+	}
+	d.addEdgeCalls += v.st.addEdgeCalls
+	d.deleteCalls += v.st.deleteCalls
+
+	return nil
+}
+
+func (d *denseGraph) Init() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.unsafeCheckFrozen() != nil {
+		return
+	}
+
+	d.unsafeReset()
+}
+
+func (d *denseGraph) ReplaceContents(other Graph) error {
+	// 先拷贝 other，避免同时持有两个图的锁，other 与 d 相同时也不会死锁
+	ng := snapshotGraph(other).unsafeRekey(d.cfg)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if err := d.unsafeCheckFrozen(); err != nil {
+		return err
+	}
+
+	if d.cfg.maxNodes > 0 && len(ng.nodeList) > d.cfg.maxNodes {
+		return ErrCapacityExceeded
+	}
+
+	d.unsafeLoad(ng)
+	if d.order != nil {
+		// other 的添加顺序无法得知，按 (From, To) 的顺序重新记录
+		d.order = newEdgeOrder()
+		for _, e := range ng.unsafeEdges() {
+			d.order.add(e.From, e.To)
+		}
+	}
+
+	return nil
+}
+
+func (d *denseGraph) Freeze() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.frozen = true
+}
+
+func (d *denseGraph) IsFrozen() bool {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	return d.frozen
+}
+
+func (d *denseGraph) GetNodeCount() int {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	return len(d.index)
+}
+
+func (d *denseGraph) GetNode(id ID) Node {
+	nd, _ := d.LookupNode(id)
+
+	return nd
+}
+
+func (d *denseGraph) LookupNode(id ID) (Node, bool) {
+	id = d.cfg.key(id)
+
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	i, ok := d.index[id]
+	if !ok {
+		return nil, false
+	}
+
+	return d.nodes[i], true
+}
+
+func (d *denseGraph) GetNodes() map[ID]Node {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	nodes := make(map[ID]Node, len(d.index))
+	for id, i := range d.index {
+		nodes[id] = d.nodes[i]
+	}
+
+	return nodes
+}
+
+func (d *denseGraph) RangeNodes(fn func(id ID, n Node) bool) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	for i, id := range d.ids {
+		if id != nil && !fn(id, d.nodes[i]) {
+			return
+		}
+	}
+}
+
+func (d *denseGraph) ParallelForEachNode(workers int, fn func(Node)) {
+	d.mu.RLock()
+	nodes := make([]Node, 0, len(d.index))
+	for _, nd := range d.nodes {
+		if nd != nil {
+			nodes = append(nodes, nd)
+		}
+	}
+	d.mu.RUnlock()
+
+	if workers < 1 {
+		workers = 1
+	}
+	if workers > len(nodes) {
+		workers = len(nodes)
+	}
+
+	var next int64 = -1
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for {
+				i := atomic.AddInt64(&next, 1)
+				if i >= int64(len(nodes)) {
+					return
+				}
+				fn(nodes[i])
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func (d *denseGraph) AddNode(nd Node) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.unsafeCheckFrozen() != nil {
+		return false
+	}
+
+	if _, ok := d.index[d.cfg.key(nd.GetId())]; ok {
+		return false
+	}
+
+	if d.unsafeFull() {
+		return false
+	}
+
+	d.unsafeAddNode(nd)
+
+	return true
+}
+
+func (d *denseGraph) AddNodes(nds []Node) (int, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if err := d.unsafeCheckFrozen(); err != nil {
+		return 0, err
+	}
+
+	added := 0
+	for _, nd := range nds {
+		if _, ok := d.index[d.cfg.key(nd.GetId())]; ok {
+			continue
+		}
+
+		if d.unsafeFull() {
+			return added, ErrCapacityExceeded
+		}
+
+		d.unsafeAddNode(nd)
+		added++
+	}
+
+	return added, nil
+}
+
+func (d *denseGraph) ReplaceNode(nd Node) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if err := d.unsafeCheckFrozen(); err != nil {
+		return err
+	}
+
+	i, err := d.unsafeSlot(d.cfg.key(nd.GetId()))
+	if err != nil {
+		return err
+	}
+	d.nodes[i] = nd
+
+	return nil
+}
+
+func (d *denseGraph) DeleteNode(id ID) bool {
+	_, ok := d.DeleteNodeWithReport(id)
+
+	return ok
+}
+
+func (d *denseGraph) DeleteNodeWithReport(id ID) ([]Edge, bool) {
+	id = d.cfg.key(id)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.unsafeCheckFrozen() != nil {
+		return nil, false
+	}
+
+	return d.unsafeDeleteNode(id)
+}
+
+func (d *denseGraph) DeleteNodes(ids []ID) int {
+	ids = d.cfg.keys(ids)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.unsafeCheckFrozen() != nil {
+		return 0
+	}
+
+	deleted := 0
+	for _, id := range ids {
+		if _, ok := d.unsafeDeleteNode(id); ok {
+			deleted++
+		}
+	}
+
+	return deleted
+}
+
+func (d *denseGraph) AddEdge(id, pid ID, wgt float64) error {
+	id, pid = d.cfg.key(id), d.cfg.key(pid)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if err := d.unsafeCheckFrozen(); err != nil {
+		return err
+	}
+
+	d.addEdgeCalls++
+
+	j, err := d.unsafeSlot(id)
+	if err != nil {
+		return err
+	}
+
+	i, err := d.unsafeSlot(pid)
+	if err != nil {
+		return err
+	}
+
+	if err := d.cfg.validateWeight(wgt); err != nil {
+		return err
+	}
+
+	// 两个有效的权重合并后也可能溢出为无穷大或者超出 validator 允许的范围
+	if k := i*d.n + j; d.has[k] {
+		wgt = d.cfg.aggregate(d.w[k], wgt)
+		if err := d.cfg.validateWeight(wgt); err != nil {
+			return err
+		}
+	}
+
+	d.unsafeSetEdge(i, j, wgt)
+
+	return nil
+}
+
+func (d *denseGraph) ReplaceEdge(id, pid ID, wgt float64) error {
+	id, pid = d.cfg.key(id), d.cfg.key(pid)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if err := d.unsafeCheckFrozen(); err != nil {
+		return err
+	}
+
+	j, err := d.unsafeSlot(id)
+	if err != nil {
+		return err
+	}
+
+	i, err := d.unsafeSlot(pid)
+	if err != nil {
+		return err
+	}
+
+	if err := d.cfg.validateWeight(wgt); err != nil {
+		return err
+	}
+
+	d.unsafeSetEdge(i, j, wgt)
+
+	return nil
+}
+
+func (d *denseGraph) ReplaceEdges(edges []Edge) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if err := d.unsafeCheckFrozen(); err != nil {
+		return err
+	}
+
+	slots := make([][2]int, len(edges))
+	for k, e := range edges {
+		from, to := d.cfg.key(e.From), d.cfg.key(e.To)
+		i, err := d.unsafeSlot(from)
+		if err != nil {
+			return fmt.Errorf("edge %s -> %s: %w", e.From, e.To, err)
+		}
+
+		j, err := d.unsafeSlot(to)
+		if err != nil {
+			return fmt.Errorf("edge %s -> %s: %w", e.From, e.To, err)
+		}
+
+		if err := d.cfg.validateWeight(e.Weight); err != nil {
+			return fmt.Errorf("edge %s -> %s: %w", e.From, e.To, err)
+		}
+		slots[k] = [2]int{i, j}
+	}
+
+	for k, e := range edges {
+		d.unsafeSetEdge(slots[k][0], slots[k][1], e.Weight)
+	}
+
+	return nil
+}
+
+func (d *denseGraph) DeleteEdge(id, pid ID) error {
+	id, pid = d.cfg.key(id), d.cfg.key(pid)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if err := d.unsafeCheckFrozen(); err != nil {
+		return err
+	}
+
+	d.deleteCalls++
+
+	j, err := d.unsafeSlot(id)
+	if err != nil {
+		return err
+	}
+
+	i, err := d.unsafeSlot(pid)
+	if err != nil {
+		return err
+	}
+
+	d.unsafeRemoveEdge(i, j)
+
+	return nil
+}
+
+func (d *denseGraph) GetWeight(id, pid ID) (float64, error) {
+	id, pid = d.cfg.key(id), d.cfg.key(pid)
+
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	j, err := d.unsafeSlot(id)
+	if err != nil {
+		return 0.0, err
+	}
+
+	i, err := d.unsafeSlot(pid)
+	if err != nil {
+		return 0.0, err
+	}
+
+	return d.unsafeEdgeWeight(i, j)
+}
+
+func (d *denseGraph) EdgeWeight(from, to ID) (float64, error) {
+	from, to = d.cfg.key(from), d.cfg.key(to)
+
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	i, err := d.unsafeSlot(from)
+	if err != nil {
+		return 0.0, err
+	}
+
+	j, err := d.unsafeSlot(to)
+	if err != nil {
+		return 0.0, err
+	}
+
+	return d.unsafeEdgeWeight(i, j)
+}
+
+// 返回第 i 个 node 指向第 j 个 node 的权重，边不存在时返回 error，调用方需要持有读锁
+func (d *denseGraph) unsafeEdgeWeight(i, j int) (float64, error) {
+	if k := i*d.n + j; d.has[k] {
+		return d.w[k], nil
+	}
+
+	return 0.0, fmt.Errorf("no edge from %s to %s", d.ids[i], d.ids[j])
+}
+
+// 返回 from -> to 的权重，第二个返回值表示边是否存在，node 不存在时也返回 false，调用方需要持有读锁
+func (d *denseGraph) unsafeGet(from, to ID) (float64, bool) {
+	i, ok := d.index[from]
+	if !ok {
+		return 0, false
+	}
+
+	j, ok := d.index[to]
+	if !ok {
+		return 0, false
+	}
+
+	k := i*d.n + j
+
+	return d.w[k], d.has[k]
+}
+
+func (d *denseGraph) HasEdgeAtLeast(from, to ID, minWeight float64) bool {
+	from, to = d.cfg.key(from), d.cfg.key(to)
+
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	w, ok := d.unsafeGet(from, to)
+
+	return ok && w >= minWeight
+}
+
+func (d *denseGraph) TargetWeight(from, to ID) (float64, bool) {
+	from, to = d.cfg.key(from), d.cfg.key(to)
+
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	return d.unsafeGet(from, to)
+}
+
+func (d *denseGraph) SourceWeight(to, from ID) (float64, bool) {
+	to, from = d.cfg.key(to), d.cfg.key(from)
+
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	return d.unsafeGet(from, to)
+}
+
+func (d *denseGraph) EdgesInOrder() []Edge {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	if d.order == nil {
+		return nil
+	}
+
+	edges := make([]Edge, 0, d.order.l.Len())
+	for e := d.order.l.Front(); e != nil; e = e.Next() {
+		k := e.Value.(edgeKey)
+		w, _ := d.unsafeGet(k.from, k.to)
+		edges = append(edges, Edge{From: k.from, To: k.to, Weight: w})
+	}
+
+	return edges
+}
+
+func (d *denseGraph) GetSources(id ID) (map[ID]Node, error) {
+	id = d.cfg.key(id)
+
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	j, err := d.unsafeSlot(id)
+	if err != nil {
+		return nil, err
+	}
+
+	s := make(map[ID]Node, d.in[j])
+	for i, sid := range d.ids {
+		if d.has[i*d.n+j] {
+			s[sid] = d.nodes[i]
+		}
+	}
+
+	return s, nil
+}
+
+func (d *denseGraph) GetTargets(pid ID) (map[ID]Node, error) {
+	pid = d.cfg.key(pid)
+
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	i, err := d.unsafeSlot(pid)
+	if err != nil {
+		return nil, err
+	}
+
+	t := make(map[ID]Node, d.out[i])
+	for j, tid := range d.ids {
+		if d.has[i*d.n+j] {
+			t[tid] = d.nodes[j]
+		}
+	}
+
+	return t, nil
+}
+
+func (d *denseGraph) RangeTargets(id ID, fn func(to ID, weight float64) bool) error {
+	id = d.cfg.key(id)
+
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	i, err := d.unsafeSlot(id)
+	if err != nil {
+		return err
+	}
+
+	// 一行在内存中是连续的
+	row := i * d.n
+	for j, tid := range d.ids {
+		if d.has[row+j] && !fn(tid, d.w[row+j]) {
+			break
+		}
+	}
+
+	return nil
+}
+
+func (d *denseGraph) RangeSources(id ID, fn func(from ID, weight float64) bool) error {
+	id = d.cfg.key(id)
+
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	j, err := d.unsafeSlot(id)
+	if err != nil {
+		return err
+	}
+
+	for i, sid := range d.ids {
+		if k := i*d.n + j; d.has[k] && !fn(sid, d.w[k]) {
+			break
+		}
+	}
+
+	return nil
+}
+
+// 以无向图的视角返回第 i 个 node 的邻居及权重，自环只计算一次，调用方需要持有读锁
+func (d *denseGraph) unsafeNeighborWeights(i int) map[ID]float64 {
+	n := make(map[ID]float64, d.out[i]+d.in[i])
+	for j, nid := range d.ids {
+		if k := i*d.n + j; d.has[k] {
+			n[nid] += d.w[k]
+		}
+
+		if k := j*d.n + i; d.has[k] && j != i {
+			n[nid] += d.w[k]
+		}
+	}
+
+	return n
+}
+
+func (d *denseGraph) Neighbors(id ID) (map[ID]Node, error) {
+	id = d.cfg.key(id)
+
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	i, err := d.unsafeSlot(id)
+	if err != nil {
+		return nil, err
+	}
+
+	n := make(map[ID]Node)
+	for nid := range d.unsafeNeighborWeights(i) {
+		n[nid] = d.nodes[d.index[nid]]
+	}
+
+	return n, nil
+}
+
+func (d *denseGraph) NeighborsWithWeights(id ID) (map[ID]float64, error) {
+	id = d.cfg.key(id)
+
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	i, err := d.unsafeSlot(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return d.unsafeNeighborWeights(i), nil
+}
+
+func (d *denseGraph) InDegree(id ID) (int, error) {
+	id = d.cfg.key(id)
+
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	i, err := d.unsafeSlot(id)
+	if err != nil {
+		return 0, err
+	}
+
+	return d.in[i], nil
+}
+
+func (d *denseGraph) OutDegree(id ID) (int, error) {
+	id = d.cfg.key(id)
+
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	i, err := d.unsafeSlot(id)
+	if err != nil {
+		return 0, err
+	}
+
+	return d.out[i], nil
+}
+
+func (d *denseGraph) SourceNodes() []ID {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	ids := make([]ID, 0)
+	for i, id := range d.ids {
+		if id != nil && d.in[i] == 0 {
+			ids = append(ids, id)
+		}
+	}
+	sortIDs(ids)
+
+	return ids
+}
+
+func (d *denseGraph) TransposeInPlace() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.unsafeCheckFrozen() != nil {
+		return
+	}
+
+	for i := range d.ids {
+		for j := i + 1; j < len(d.ids); j++ {
+			a, b := i*d.n+j, j*d.n+i
+			d.w[a], d.w[b] = d.w[b], d.w[a]
+			d.has[a], d.has[b] = d.has[b], d.has[a]
+		}
+	}
+	d.in, d.out = d.out, d.in
+
+	if d.order != nil {
+		d.order.reverse()
+	}
+}
+
+func (d *denseGraph) Stats() GraphStats {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	maxDegree := 0
+	for i, id := range d.ids {
+		if id != nil && d.in[i]+d.out[i] > maxDegree {
+			maxDegree = d.in[i] + d.out[i]
+		}
+	}
+
+	return GraphStats{
+		Nodes:             len(d.index),
+		Edges:             d.edges,
+		TotalAddEdgeCalls: d.addEdgeCalls,
+		TotalDeleteCalls:  d.deleteCalls,
+		MaxDegree:         maxDegree,
+	}
+}
@@ -0,0 +1,292 @@
+package kraph
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+// 比较两个图的 node、边、度数和统计信息，权重允许浮点误差
+func checkSameGraph(t *testing.T, got, want Graph, ids []ID) {
+	t.Helper()
+
+	if got.GetNodeCount() != want.GetNodeCount() {
+		t.Fatalf("node count = %d, want %d", got.GetNodeCount(), want.GetNodeCount())
+	}
+	if gs, ws := got.Stats(), want.Stats(); gs != ws {
+		t.Fatalf("Stats() = %+v, want %+v", gs, ws)
+	}
+
+	for _, from := range ids {
+		if _, ok := want.LookupNode(from); !ok {
+			if _, ok := got.LookupNode(from); ok {
+				t.Fatalf("unexpected node %s", from)
+			}
+			continue
+		}
+
+		gin, _ := got.InDegree(from)
+		win, _ := want.InDegree(from)
+		gout, _ := got.OutDegree(from)
+		wout, _ := want.OutDegree(from)
+		if gin != win || gout != wout {
+			t.Fatalf("degree of %s = %d/%d, want %d/%d", from, gin, gout, win, wout)
+		}
+
+		for _, to := range ids {
+			w1, err1 := got.EdgeWeight(from, to)
+			w2, err2 := want.EdgeWeight(from, to)
+			if (err1 == nil) != (err2 == nil) || math.Abs(w1-w2) > 1e-9 {
+				t.Fatalf("EdgeWeight(%s, %s) = %v, %v; want %v, %v", from, to, w1, err1, w2, err2)
+			}
+			if w, ok := got.SourceWeight(to, from); ok != (err2 == nil) || math.Abs(w-w2) > 1e-9 {
+				t.Fatalf("SourceWeight(%s, %s) = %v, %v", to, from, w, ok)
+			}
+		}
+	}
+}
+
+func TestDenseGraphMatchesGraph(t *testing.T) {
+	// capacity 很小，保证过程中会多次扩容
+	dg, g := NewDenseGraph(2), NewGraph()
+	ids := make([]ID, 30)
+	for i := range ids {
+		ids[i] = NewNid("n" + strconv.Itoa(i))
+	}
+
+	r := rand.New(rand.NewSource(1))
+	for step := 0; step < 3000; step++ {
+		a, b := ids[r.Intn(len(ids))], ids[r.Intn(len(ids))]
+		op := r.Intn(20)
+		var errs [2]error
+		for k, x := range []Graph{dg, g} {
+			var err error
+			switch {
+			case op < 5:
+				x.AddNode(NewNode(a))
+				x.AddNode(NewNode(b))
+			case op < 10:
+				err = x.AddEdge(b, a, float64(step%13))
+			case op < 12:
+				err = x.ReplaceEdge(b, a, float64(step%7))
+			case op < 14:
+				err = x.DeleteEdge(b, a)
+			case op == 14:
+				x.DeleteNode(a)
+			case op == 15:
+				err = x.ReverseEdge(a, b)
+			case op == 16:
+				err = x.AddEdgeIfAcyclic(a, b, 1)
+			case op == 17 && step%10 == 0:
+				err = x.RemoveAndBypass(a)
+			case op == 18 && step%50 == 0:
+				x.TransposeInPlace()
+			case op == 19 && step%100 == 0:
+				x.NormalizeOutWeights()
+			}
+			errs[k] = err
+		}
+		if (errs[0] == nil) != (errs[1] == nil) {
+			t.Fatalf("step %d op %d: dense returned %v, map returned %v", step, op, errs[0], errs[1])
+		}
+	}
+	checkSameGraph(t, dg, g, ids)
+
+	// 由临时邻接表实现的算法与 graph 的结果相同
+	if got, want := dg.SourceNodes(), g.SourceNodes(); !reflect.DeepEqual(got, want) {
+		t.Errorf("SourceNodes() = %v, want %v", got, want)
+	}
+	if got, want := dg.ComponentSizes(), g.ComponentSizes(); !reflect.DeepEqual(got, want) {
+		t.Errorf("ComponentSizes() = %v, want %v", got, want)
+	}
+	if got, want := dg.String(), g.String(); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+
+	dg.ReplaceContents(g)
+	checkSameGraph(t, dg, g, ids)
+
+	dg.Init()
+	if dg.GetNodeCount() != 0 || dg.Stats().Edges != 0 {
+		t.Errorf("Init left %d nodes, %d edges", dg.GetNodeCount(), dg.Stats().Edges)
+	}
+	if _, ok := dg.TargetWeight(ids[0], ids[1]); ok {
+		t.Error("edge should not survive Init")
+	}
+}
+
+func TestDenseGraphErrorsMatchGraph(t *testing.T) {
+	a, b, c := NewNid("a"), NewNid("b"), NewNid("c")
+	for _, x := range []Graph{NewDenseGraph(4), NewGraph()} {
+		x.AddNode(NewNode(a))
+		x.AddNode(NewNode(b))
+
+		if err := x.AddEdge(c, a, 1); err == nil || err.Error() != "c does not exist in graph" {
+			t.Errorf("%T AddEdge to missing node = %v", x, err)
+		}
+		if _, err := x.EdgeWeight(a, b); err == nil || err.Error() != "no edge from a to b" {
+			t.Errorf("%T EdgeWeight of missing edge = %v", x, err)
+		}
+		if err := x.AddEdge(b, a, math.NaN()); !errors.Is(err, ErrNonFiniteWeight) {
+			t.Errorf("%T AddEdge(NaN) = %v", x, err)
+		}
+		if err := x.ReverseEdge(b, a); err == nil {
+			t.Errorf("%T ReverseEdge of missing edge should fail", x)
+		}
+	}
+}
+
+func TestDenseGraphOptions(t *testing.T) {
+	a, b, c := NewNid("a"), NewNid("b"), NewNid("c")
+
+	// 添加顺序在 TransposeInPlace 和 ReverseEdge 之后与 graph 一致
+	dg, g := NewDenseGraph(2, WithInsertionOrder()), NewGraph(WithInsertionOrder())
+	for _, x := range []Graph{dg, g} {
+		for _, id := range []ID{c, b, a} {
+			x.AddNode(NewNode(id))
+		}
+		x.AddEdge(a, c, 1)
+		x.AddEdge(b, a, 2)
+		x.AddEdge(c, b, 3)
+		x.DeleteEdge(a, c)
+		x.AddEdge(a, c, 4)
+		x.TransposeInPlace()
+		x.ReverseEdge(a, b)
+	}
+	if got, want := dg.EdgesInOrder(), g.EdgesInOrder(); !reflect.DeepEqual(got, want) {
+		t.Errorf("EdgesInOrder() = %v, want %v", got, want)
+	}
+
+	// 大小写不敏感的 id
+	ci := NewDenseGraph(4, WithCaseInsensitiveIDs())
+	ci.AddNode(NewNode(NewNid("A")))
+	ci.AddNode(NewNode(NewNid("B")))
+	if err := ci.AddEdge(NewNid("b"), NewNid("a"), 1); err != nil {
+		t.Fatal(err)
+	}
+	if w, ok := ci.TargetWeight(NewNid("A"), NewNid("B")); !ok || w != 1 {
+		t.Errorf("TargetWeight(A, B) = %v, %v", w, ok)
+	}
+
+	// node 数量上限
+	capped := NewDenseGraph(1, WithMaxNodes(2))
+	if added, err := capped.AddNodes([]Node{NewNode(a), NewNode(b), NewNode(c)}); added != 2 || err != ErrCapacityExceeded {
+		t.Errorf("AddNodes() = %d, %v; want 2, ErrCapacityExceeded", added, err)
+	}
+
+	// 合并后的权重同样需要通过校验
+	errTooLarge := errors.New("weight too large")
+	vg := NewDenseGraph(2, WithWeightValidator(func(w float64) error {
+		if w > 10 {
+			return errTooLarge
+		}
+		return nil
+	}))
+	vg.AddNode(NewNode(a))
+	vg.AddNode(NewNode(b))
+	vg.AddEdge(b, a, 6)
+	if err := vg.AddEdge(b, a, 6); err != errTooLarge {
+		t.Errorf("AddEdge merged sum = %v, want errTooLarge", err)
+	}
+	if err := vg.ScaleWeights(func(w float64) float64 { return w * 2 }); err == nil {
+		t.Error("ScaleWeights over validator bound should fail")
+	}
+	if w, _ := vg.EdgeWeight(a, b); w != 6 {
+		t.Errorf("weight = %v after rejected calls, want 6", w)
+	}
+
+	// 冻结之后所有修改都被拒绝，包括通过临时邻接表实现的方法
+	vg.Freeze()
+	if err := vg.AddEdge(b, a, 1); err != ErrFrozen {
+		t.Errorf("AddEdge on frozen graph = %v", err)
+	}
+	if err := vg.RemoveAndBypass(a); err != ErrFrozen {
+		t.Errorf("RemoveAndBypass on frozen graph = %v", err)
+	}
+	vg.NormalizeOutWeights()
+	if w, _ := vg.EdgeWeight(a, b); w != 6 {
+		t.Errorf("NormalizeOutWeights modified a frozen graph: %v", w)
+	}
+}
+
+// 构建一个 n 个 node、每个 node 有 deg 条出边的图
+func benchGraph(g Graph, n, deg int) []ID {
+	ids := make([]ID, n)
+	for i := range ids {
+		ids[i] = NewNid("n" + strconv.Itoa(i))
+		g.AddNode(NewNode(ids[i]))
+	}
+	for i := range ids {
+		for k := 1; k <= deg; k++ {
+			g.AddEdge(ids[(i+k*7)%n], ids[i], float64(k))
+		}
+	}
+
+	return ids
+}
+
+var benchSizes = []int{50, 200, 400}
+
+func BenchmarkBuild(b *testing.B) {
+	for _, n := range benchSizes {
+		b.Run("map/"+strconv.Itoa(n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				benchGraph(NewGraph(), n, n/4)
+			}
+		})
+		b.Run("dense/"+strconv.Itoa(n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				benchGraph(NewDenseGraph(n), n, n/4)
+			}
+		})
+	}
+}
+
+// 查询所有 node 对之间的权重，约四分之一的查询命中
+func BenchmarkTargetWeight(b *testing.B) {
+	for _, n := range benchSizes {
+		for _, impl := range []struct {
+			name string
+			g    Graph
+		}{{"map", NewGraph()}, {"dense", NewDenseGraph(n)}} {
+			g := impl.g
+			ids := benchGraph(g, n, n/4)
+			b.Run(impl.name+"/"+strconv.Itoa(n), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					for _, from := range ids {
+						for _, to := range ids {
+							g.TargetWeight(from, to)
+						}
+					}
+				}
+			})
+		}
+	}
+}
+
+// 遍历所有 node 的下游边并累加权重
+func BenchmarkRangeTargets(b *testing.B) {
+	for _, n := range benchSizes {
+		for _, impl := range []struct {
+			name string
+			g    Graph
+		}{{"map", NewGraph()}, {"dense", NewDenseGraph(n)}} {
+			g := impl.g
+			ids := benchGraph(g, n, n/4)
+			b.Run(impl.name+"/"+strconv.Itoa(n), func(b *testing.B) {
+				sum := 0.0
+				for i := 0; i < b.N; i++ {
+					for _, id := range ids {
+						g.RangeTargets(id, func(_ ID, w float64) bool {
+							sum += w
+							return true
+						})
+					}
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,348 @@
+package kraph
+
+import (
+	"context"
+	"io"
+	"math/rand"
+)
+
+// 以下方法还没有基于矩阵实现，调用时先在读锁内根据矩阵构建一份临时的邻接表，再在它上面运行 graph 的实现
+// 会修改图的方法通过 modify 在写锁内运行，成功后把结果写回矩阵
+
+func (d *denseGraph) EdgesSortedByWeight(descending bool) []Edge {
+	return d.view().EdgesSortedByWeight(descending)
+}
+
+func (d *denseGraph) StreamEdges(ctx context.Context) <-chan Edge {
+	return d.view().StreamEdges(ctx)
+}
+
+func (d *denseGraph) SelfLoops() []Edge {
+	return d.view().SelfLoops()
+}
+
+func (d *denseGraph) SortedTargets(id ID) ([]Edge, error) {
+	return d.view().SortedTargets(id)
+}
+
+func (d *denseGraph) SortedSources(id ID) ([]Edge, error) {
+	return d.view().SortedSources(id)
+}
+
+func (d *denseGraph) IncidentEdges(id ID) ([]Edge, error) {
+	return d.view().IncidentEdges(id)
+}
+
+func (d *denseGraph) SampleTarget(id ID, rng *rand.Rand) (ID, error) {
+	return d.view().SampleTarget(id, rng)
+}
+
+func (d *denseGraph) HeaviestSource(id ID) (ID, float64, error) {
+	return d.view().HeaviestSource(id)
+}
+
+func (d *denseGraph) HeaviestTarget(id ID) (ID, float64, error) {
+	return d.view().HeaviestTarget(id)
+}
+
+func (d *denseGraph) DegreeAssortativity() float64 {
+	return d.view().DegreeAssortativity()
+}
+
+func (d *denseGraph) AverageDegree() float64 {
+	return d.view().AverageDegree()
+}
+
+func (d *denseGraph) DegreeDistribution() map[int]int {
+	return d.view().DegreeDistribution()
+}
+
+func (d *denseGraph) CommonNeighbors(a, b ID, undirected bool) ([]ID, error) {
+	return d.view().CommonNeighbors(a, b, undirected)
+}
+
+func (d *denseGraph) JaccardSimilarity(a, b ID) (float64, error) {
+	return d.view().JaccardSimilarity(a, b)
+}
+
+func (d *denseGraph) CosineSimilarity(a, b ID) (float64, error) {
+	return d.view().CosineSimilarity(a, b)
+}
+
+func (d *denseGraph) SimRank(c float64, iterations int, threshold float64) map[[2]ID]float64 {
+	return d.view().SimRank(c, iterations, threshold)
+}
+
+func (d *denseGraph) GreedyIndependentSet() []ID {
+	return d.view().GreedyIndependentSet()
+}
+
+func (d *denseGraph) DegeneracyOrdering() ([]ID, int) {
+	return d.view().DegeneracyOrdering()
+}
+
+func (d *denseGraph) VertexCover() []ID {
+	return d.view().VertexCover()
+}
+
+func (d *denseGraph) Complement(wgt float64) (Graph, error) {
+	return d.view().Complement(wgt)
+}
+
+func (d *denseGraph) Union(other Graph) (Graph, error) {
+	return d.view().Union(other)
+}
+
+func (d *denseGraph) Intersection(other Graph) Graph {
+	return d.view().Intersection(other)
+}
+
+func (d *denseGraph) Difference(other Graph) Graph {
+	return d.view().Difference(other)
+}
+
+func (d *denseGraph) FilterNodes(keep func(Node) bool) Graph {
+	return d.view().FilterNodes(keep)
+}
+
+func (d *denseGraph) FilterEdges(keep func(from, to ID, w float64) bool) Graph {
+	return d.view().FilterEdges(keep)
+}
+
+func (d *denseGraph) ReachableSubgraph(roots []ID) (Graph, error) {
+	return d.view().ReachableSubgraph(roots)
+}
+
+func (d *denseGraph) SpanningForest() (Graph, []ID) {
+	return d.view().SpanningForest()
+}
+
+func (d *denseGraph) CollapseMultiEdges(combine func(weights []float64) float64) (Graph, error) {
+	return d.view().CollapseMultiEdges(combine)
+}
+
+func (d *denseGraph) ShortestPathAvoiding(from, to ID, avoid map[ID]bool) ([]ID, float64, error) {
+	return d.view().ShortestPathAvoiding(from, to, avoid)
+}
+
+func (d *denseGraph) ShortestPathTree(source ID) (dist map[ID]float64, prev map[ID]ID, err error) {
+	return d.view().ShortestPathTree(source)
+}
+
+func (d *denseGraph) WidestPath(from, to ID) ([]ID, float64, error) {
+	return d.view().WidestPath(from, to)
+}
+
+func (d *denseGraph) ReachableWithinBudget(from ID, budget float64) (map[ID]float64, error) {
+	return d.view().ReachableWithinBudget(from, budget)
+}
+
+func (d *denseGraph) AStar(from, to ID, heuristic func(ID) float64) ([]ID, float64, error) {
+	return d.view().AStar(from, to, heuristic)
+}
+
+func (d *denseGraph) KShortestPaths(from, to ID, k int) ([]Path, error) {
+	return d.view().KShortestPaths(from, to, k)
+}
+
+func (d *denseGraph) ShortestPathBidirectional(from, to ID) ([]ID, float64, error) {
+	return d.view().ShortestPathBidirectional(from, to)
+}
+
+func (d *denseGraph) MaxWeightPath(from, to ID) ([]ID, float64, error) {
+	return d.view().MaxWeightPath(from, to)
+}
+
+func (d *denseGraph) CountPaths(from, to ID) (int64, error) {
+	return d.view().CountPaths(from, to)
+}
+
+func (d *denseGraph) IsDAG() bool {
+	return d.view().IsDAG()
+}
+
+func (d *denseGraph) WouldCreateCycle(from, to ID) (bool, error) {
+	return d.view().WouldCreateCycle(from, to)
+}
+
+func (d *denseGraph) AllPairsJohnson() (map[ID]map[ID]float64, error) {
+	return d.view().AllPairsJohnson()
+}
+
+func (d *denseGraph) EulerianPath() ([]ID, error) {
+	return d.view().EulerianPath()
+}
+
+func (d *denseGraph) MultiSourceDistances(sources []ID) (map[ID]int, error) {
+	return d.view().MultiSourceDistances(sources)
+}
+
+func (d *denseGraph) WalkByWeight(start ID, visit func(from, to ID, w float64) bool) error {
+	return d.view().WalkByWeight(start, visit)
+}
+
+func (d *denseGraph) AllCycles() [][]ID {
+	return d.view().AllCycles()
+}
+
+func (d *denseGraph) Condensation() (Graph, map[ID]ID, error) {
+	return d.view().Condensation()
+}
+
+func (d *denseGraph) DescendantCounts() map[ID]int {
+	return d.view().DescendantCounts()
+}
+
+func (d *denseGraph) InSameSCC(a, b ID) (bool, error) {
+	return d.view().InSameSCC(a, b)
+}
+
+func (d *denseGraph) ComponentSizes() []int {
+	return d.view().ComponentSizes()
+}
+
+func (d *denseGraph) Connected(a, b ID) bool {
+	return d.view().Connected(a, b)
+}
+
+func (d *denseGraph) WeightHistogram(bins int) ([]int, float64, float64) {
+	return d.view().WeightHistogram(bins)
+}
+
+func (d *denseGraph) Eccentricity(id ID) (int, error) {
+	return d.view().Eccentricity(id)
+}
+
+func (d *denseGraph) Diameter() (int, error) {
+	return d.view().Diameter()
+}
+
+func (d *denseGraph) Radius() (int, error) {
+	return d.view().Radius()
+}
+
+func (d *denseGraph) Center() []ID {
+	return d.view().Center()
+}
+
+func (d *denseGraph) LabelPropagation(maxIter int, rng *rand.Rand) map[ID]int {
+	return d.view().LabelPropagation(maxIter, rng)
+}
+
+func (d *denseGraph) Louvain() (map[ID]int, float64) {
+	return d.view().Louvain()
+}
+
+func (d *denseGraph) Modularity(partition map[ID]int) float64 {
+	return d.view().Modularity(partition)
+}
+
+func (d *denseGraph) EigenvectorCentrality(iterations int, tolerance float64) (map[ID]float64, error) {
+	return d.view().EigenvectorCentrality(iterations, tolerance)
+}
+
+func (d *denseGraph) KatzCentrality(alpha, beta float64, iterations int) (map[ID]float64, error) {
+	return d.view().KatzCentrality(alpha, beta, iterations)
+}
+
+func (d *denseGraph) PageRank(damping float64, iterations int) map[ID]float64 {
+	return d.view().PageRank(damping, iterations)
+}
+
+func (d *denseGraph) PageRankParallel(damping float64, iterations int, workers int) map[ID]float64 {
+	return d.view().PageRankParallel(damping, iterations, workers)
+}
+
+func (d *denseGraph) WeightedPageRank(damping float64, iterations int) map[ID]float64 {
+	return d.view().WeightedPageRank(damping, iterations)
+}
+
+func (d *denseGraph) PersonalizedPageRank(seeds map[ID]float64, damping float64, iterations int) map[ID]float64 {
+	return d.view().PersonalizedPageRank(seeds, damping, iterations)
+}
+
+func (d *denseGraph) JSON() ([]byte, error) {
+	return d.view().JSON()
+}
+
+func (d *denseGraph) JSONSnapshot() ([]byte, error) {
+	return d.view().JSONSnapshot()
+}
+
+func (d *denseGraph) WriteJSON(w io.Writer) error {
+	return d.view().WriteJSON(w)
+}
+
+func (d *denseGraph) JSONOrdered() ([]byte, error) {
+	return d.view().JSONOrdered()
+}
+
+func (d *denseGraph) NetworkXJSON() ([]byte, error) {
+	return d.view().NetworkXJSON()
+}
+
+func (d *denseGraph) WriteAdjacencyList(w io.Writer) error {
+	return d.view().WriteAdjacencyList(w)
+}
+
+func (d *denseGraph) String() string {
+	return d.view().String()
+}
+
+func (d *denseGraph) GraphML(w io.Writer) error {
+	return d.view().GraphML(w)
+}
+
+func (d *denseGraph) GEXF(w io.Writer) error {
+	return d.view().GEXF(w)
+}
+
+func (d *denseGraph) AddEdgeIfAcyclic(from, to ID, wgt float64) error {
+	return d.modify(func(v *graph) error {
+		return v.AddEdgeIfAcyclic(from, to, wgt)
+	})
+}
+
+func (d *denseGraph) NormalizeOutWeights() {
+	d.modify(func(v *graph) error {
+		v.NormalizeOutWeights()
+		return nil
+	})
+}
+
+func (d *denseGraph) NormalizeInWeights() {
+	d.modify(func(v *graph) error {
+		v.NormalizeInWeights()
+		return nil
+	})
+}
+
+func (d *denseGraph) ScaleWeights(f func(float64) float64) error {
+	return d.modify(func(v *graph) error {
+		return v.ScaleWeights(f)
+	})
+}
+
+func (d *denseGraph) ReverseEdge(from, to ID) error {
+	return d.modify(func(v *graph) error {
+		return v.ReverseEdge(from, to)
+	})
+}
+
+func (d *denseGraph) SplitNode(id, newID ID, assignTarget func(to ID) bool) error {
+	return d.modify(func(v *graph) error {
+		return v.SplitNode(id, newID, assignTarget)
+	})
+}
+
+func (d *denseGraph) RemoveAndBypass(id ID) error {
+	return d.modify(func(v *graph) error {
+		return v.RemoveAndBypass(id)
+	})
+}
+
+func (d *denseGraph) RemoveAndBypassWith(id ID, combine func(in, out float64) float64) error {
+	return d.modify(func(v *graph) error {
+		return v.RemoveAndBypassWith(id, combine)
+	})
+}
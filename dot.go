@@ -0,0 +1,291 @@
+package kraph
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+type dotToken struct {
+	text   string
+	quoted bool
+}
+
+// 判断 token 是否为未加引号的 s，关键字不区分大小写
+func (t dotToken) is(s string) bool {
+	return !t.quoted && strings.EqualFold(t.text, s)
+}
+
+func isDotIDByte(c byte) bool {
+	return c == '_' || c == '.' || c == '-' || c >= 0x80 ||
+		(c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+// 将 DOT 文本拆分为 token，忽略注释，引号中的字符串会被还原转义
+func dotTokens(src string) ([]dotToken, error) {
+	toks := make([]dotToken, 0)
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '#' || strings.HasPrefix(src[i:], "//"):
+			for i < len(src) && src[i] != '\n' {
+				i++
+			}
+		case strings.HasPrefix(src[i:], "/*"):
+			end := strings.Index(src[i+2:], "*/")
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated comment")
+			}
+			i += end + 4
+		case strings.HasPrefix(src[i:], "->") || strings.HasPrefix(src[i:], "--"):
+			toks = append(toks, dotToken{text: src[i : i+2]})
+			i += 2
+		case strings.IndexByte("{}[]=;,", c) >= 0:
+			toks = append(toks, dotToken{text: string(c)})
+			i++
+		case c == '"':
+			var b strings.Builder
+			j := i + 1
+			for ; j < len(src) && src[j] != '"'; j++ {
+				if src[j] == '\\' && j+1 < len(src) && src[j+1] == '"' {
+					j++
+				}
+				b.WriteByte(src[j])
+			}
+			if j >= len(src) {
+				return nil, fmt.Errorf("unterminated string")
+			}
+			toks = append(toks, dotToken{text: b.String(), quoted: true})
+			i = j + 1
+		case isDotIDByte(c):
+			j := i
+			for j < len(src) && isDotIDByte(src[j]) && !strings.HasPrefix(src[j:], "->") && !strings.HasPrefix(src[j:], "--") {
+				j++
+			}
+			toks = append(toks, dotToken{text: src[i:j]})
+			i = j
+		default:
+			return nil, fmt.Errorf("unexpected character %q", c)
+		}
+	}
+
+	return toks, nil
+}
+
+type dotParser struct {
+	toks []dotToken
+	pos  int
+}
+
+func (p *dotParser) peek() (dotToken, bool) {
+	if p.pos >= len(p.toks) {
+		return dotToken{}, false
+	}
+
+	return p.toks[p.pos], true
+}
+
+func (p *dotParser) next() (dotToken, error) {
+	t, ok := p.peek()
+	if !ok {
+		return t, fmt.Errorf("unexpected end of input")
+	}
+	p.pos++
+
+	return t, nil
+}
+
+func (p *dotParser) expect(s string) error {
+	t, err := p.next()
+	if err != nil {
+		return err
+	}
+	if !t.is(s) {
+		return fmt.Errorf("expected %q, got %q", s, t.text)
+	}
+
+	return nil
+}
+
+// 判断 token 是否可以作为 node id 或属性值
+func (t dotToken) isID() bool {
+	return t.quoted || (t.text != "" && isDotIDByte(t.text[0]))
+}
+
+// 读取零个或多个 [k=v, ...] 属性列表
+func (p *dotParser) attrs() (map[string]string, error) {
+	attrs := make(map[string]string)
+	for {
+		t, ok := p.peek()
+		if !ok || !t.is("[") {
+			return attrs, nil
+		}
+		p.pos++
+
+		for {
+			t, err := p.next()
+			if err != nil {
+				return nil, err
+			}
+			if t.is("]") {
+				break
+			}
+			if t.is(",") || t.is(";") {
+				continue
+			}
+			if !t.isID() {
+				return nil, fmt.Errorf("unexpected %q in attribute list", t.text)
+			}
+
+			if err := p.expect("="); err != nil {
+				return nil, err
+			}
+			v, err := p.next()
+			if err != nil {
+				return nil, err
+			}
+			if !v.isID() {
+				return nil, fmt.Errorf("invalid value %q for attribute %s", v.text, t.text)
+			}
+			attrs[t.text] = v.text
+		}
+	}
+}
+
+// LoadDOT 从 Graphviz DOT 文本中读取 node 和边并构建 graph，只支持 DOT 的以下子集：
+//
+//	[strict] (digraph | graph) [名称] { 语句 ... }
+//	语句：  名称 [属性列表]                     node 语句
+//	       名称 -> 名称 [-> 名称 ...] [属性列表]  边语句，graph 中使用 --
+//	       (graph | node | edge) [属性列表]     默认属性，会被忽略
+//	       k = v                             图的属性，会被忽略
+//	属性列表：[k=v, ...]，可以连续出现多个
+//
+// 名称可以是由字母、数字、非 ASCII 字符、_、. 和 - 组成的裸字符串，也可以是带 \" 转义的双引号字符串；语句之间可以用 ; 或 , 分隔，
+// 支持 //、/* */ 和 # 注释，关键字不区分大小写，不支持 subgraph 和 HTML 字符串
+// 边的 label 属性被解析为权重，没有 label 时权重为 1，其它属性会被忽略，graph 中的 -- 边会被拆分成两条方向相反的有向边
+func LoadDOT(r io.Reader, opts ...Option) (Graph, error) {
+	src, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	toks, err := dotTokens(string(src))
+	if err != nil {
+		return nil, err
+	}
+	p := &dotParser{toks: toks}
+
+	t, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+	if t.is("strict") {
+		if t, err = p.next(); err != nil {
+			return nil, err
+		}
+	}
+
+	var directed bool
+	switch {
+	case t.is("digraph"):
+		directed = true
+	case t.is("graph"):
+		directed = false
+	default:
+		return nil, fmt.Errorf("expected digraph or graph, got %q", t.text)
+	}
+
+	if t, ok := p.peek(); ok && t.isID() {
+		p.pos++
+	}
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+
+	g := NewGraph(opts...)
+	for {
+		t, err := p.next()
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case t.is("}"):
+			return g, nil
+		case t.is(";") || t.is(","):
+			continue
+		case t.is("{") || t.is("subgraph"):
+			return nil, fmt.Errorf("subgraphs are not supported")
+		case t.is("graph") || t.is("node") || t.is("edge"):
+			if _, err := p.attrs(); err != nil {
+				return nil, err
+			}
+			continue
+		case !t.isID():
+			return nil, fmt.Errorf("unexpected %q", t.text)
+		}
+
+		// 图的属性 k = v
+		if nt, ok := p.peek(); ok && nt.is("=") {
+			p.pos++
+			if _, err := p.next(); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		chain := []ID{NewNid(t.text)}
+		for {
+			op, ok := p.peek()
+			if !ok || !(op.is("->") || op.is("--")) {
+				break
+			}
+			if op.is("->") != directed {
+				return nil, fmt.Errorf("edge operator %s does not match graph type", op.text)
+			}
+			p.pos++
+
+			nt, err := p.next()
+			if err != nil {
+				return nil, err
+			}
+			if !nt.isID() {
+				return nil, fmt.Errorf("unexpected %q after %s", nt.text, op.text)
+			}
+			chain = append(chain, NewNid(nt.text))
+		}
+
+		attrs, err := p.attrs()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, id := range chain {
+			g.AddNode(NewNode(id))
+		}
+
+		wgt := 1.0
+		if label, ok := attrs["label"]; ok && len(chain) > 1 {
+			wgt, err = strconv.ParseFloat(strings.TrimSpace(label), 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid weight %q on edge %s -> %s: %v", label, chain[0], chain[1], err)
+			}
+		}
+
+		for i := 1; i < len(chain); i++ {
+			from, to := chain[i-1], chain[i]
+			if err := g.AddEdge(to, from, wgt); err != nil {
+				return nil, err
+			}
+			if !directed && from != to {
+				if err := g.AddEdge(from, to, wgt); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+}
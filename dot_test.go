@@ -0,0 +1,81 @@
+package kraph
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLoadDOT(t *testing.T) {
+	doc := `// 手写的图
+digraph G {
+	rankdir = LR;
+	node [shape=circle];
+	a;
+	"node b" [color=red];
+	a -> "node b" [label="1.5"];
+	"node b" -> c -> d [label=2, color="blue"];
+	d -> a /* 没有 label */;
+	c -> c [label="-0.5"]
+}`
+
+	g, err := LoadDOT(strings.NewReader(doc))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if g.GetNodeCount() != 4 {
+		t.Errorf("node count = %d, want 4", g.GetNodeCount())
+	}
+
+	a, b, c, d := NewNid("a"), NewNid("node b"), NewNid("c"), NewNid("d")
+	cases := []struct {
+		from, to ID
+		wgt      float64
+	}{
+		{a, b, 1.5},
+		{b, c, 2},
+		{c, d, 2},
+		{d, a, 1},
+		{c, c, -0.5},
+	}
+	for _, cs := range cases {
+		if w, err := g.GetWeight(cs.to, cs.from); err != nil || w != cs.wgt {
+			t.Errorf("edge %s -> %s = %v, %v; want %v", cs.from, cs.to, w, err, cs.wgt)
+		}
+	}
+	if _, err := g.GetWeight(a, b); err == nil {
+		t.Error("unexpected reverse edge b -> a")
+	}
+}
+
+func TestLoadDOTUndirected(t *testing.T) {
+	g, err := LoadDOT(strings.NewReader(`graph { x -- y [label=3] }`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	x, y := NewNid("x"), NewNid("y")
+	if w, _ := g.GetWeight(y, x); w != 3 {
+		t.Errorf("edge x -> y = %v, want 3", w)
+	}
+	if w, _ := g.GetWeight(x, y); w != 3 {
+		t.Errorf("edge y -> x = %v, want 3", w)
+	}
+}
+
+func TestLoadDOTErrors(t *testing.T) {
+	docs := []string{
+		`digraph { a -> b [label="heavy"] }`,
+		`digraph { a -- b }`,
+		`digraph { a -> b `,
+		`digraph { subgraph s { a } }`,
+		`strict network { a }`,
+		`digraph { "a -> b }`,
+	}
+
+	for _, doc := range docs {
+		if _, err := LoadDOT(strings.NewReader(doc)); err == nil {
+			t.Errorf("LoadDOT(%q) should fail", doc)
+		}
+	}
+}
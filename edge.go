@@ -0,0 +1,283 @@
+package kraph
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"sort"
+)
+
+// 按照 (From, To) 的字符串顺序对边排序，保证输出稳定
+func sortEdges(edges []Edge) {
+	sort.Slice(edges, func(i, j int) bool {
+		fi, fj := edges[i].From.String(), edges[j].From.String()
+		if fi != fj {
+			return fi < fj
+		}
+
+		return edges[i].To.String() < edges[j].To.String()
+	})
+}
+
+// 按照字符串顺序对 id 排序
+func sortIDs(ids []ID) {
+	sort.Slice(ids, func(i, j int) bool {
+		return ids[i].String() < ids[j].String()
+	})
+}
+
+// 返回图中所有 node 的 id，按字符串顺序排列
+func (g *graph) unsafeNodeIDs() []ID {
+	ids := make([]ID, 0, len(g.nodeList))
+	for id := range g.nodeList {
+		ids = append(ids, id)
+	}
+	sortIDs(ids)
+
+	return ids
+}
+
+// 返回图中所有的边，按 (From, To) 排列
+func (g *graph) unsafeEdges() []Edge {
+	edges := make([]Edge, 0)
+	for pid, tmap := range g.nodeTargets {
+		for id, wgt := range tmap {
+			edges = append(edges, Edge{From: pid, To: id, Weight: wgt})
+		}
+	}
+	sortEdges(edges)
+
+	return edges
+}
+
+func (g *graph) EdgesSortedByWeight(descending bool) []Edge {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	// unsafeEdges 已经按 (From, To) 排序，稳定排序可以保留这个顺序
+	edges := g.unsafeEdges()
+	sort.SliceStable(edges, func(i, j int) bool {
+		if descending {
+			return edges[i].Weight > edges[j].Weight
+		}
+
+		return edges[i].Weight < edges[j].Weight
+	})
+
+	return edges
+}
+
+func (g *graph) StreamEdges(ctx context.Context) <-chan Edge {
+	g.mu.RLock()
+	edges := g.unsafeEdges()
+	g.mu.RUnlock()
+
+	ch := make(chan Edge)
+	go func() {
+		defer close(ch)
+
+		for _, e := range edges {
+			select {
+			case ch <- e:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return ch
+}
+
+// 按添加顺序排列的边，链表保存顺序，index 用于 O(1) 删除
+type edgeOrder struct {
+	l     *list.List
+	index map[edgeKey]*list.Element
+}
+
+func newEdgeOrder() *edgeOrder {
+	return &edgeOrder{
+		l:     list.New(),
+		index: make(map[edgeKey]*list.Element),
+	}
+}
+
+func (o *edgeOrder) add(from, to ID) {
+	k := edgeKey{from, to}
+	if _, ok := o.index[k]; !ok {
+		o.index[k] = o.l.PushBack(k)
+	}
+}
+
+func (o *edgeOrder) remove(from, to ID) {
+	k := edgeKey{from, to}
+	if e, ok := o.index[k]; ok {
+		o.l.Remove(e)
+		delete(o.index, k)
+	}
+}
+
+// 返回一份独立的拷贝，修改拷贝不会影响 o
+func (o *edgeOrder) clone() *edgeOrder {
+	c := newEdgeOrder()
+	for e := o.l.Front(); e != nil; e = e.Next() {
+		k := e.Value.(edgeKey)
+		c.add(k.from, k.to)
+	}
+
+	return c
+}
+
+// 将每条边的方向反转，顺序不变
+func (o *edgeOrder) reverse() {
+	o.index = make(map[edgeKey]*list.Element, len(o.index))
+	for e := o.l.Front(); e != nil; e = e.Next() {
+		k := e.Value.(edgeKey)
+		e.Value = edgeKey{k.to, k.from}
+		o.index[e.Value.(edgeKey)] = e
+	}
+}
+
+func (g *graph) EdgesInOrder() []Edge {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	if g.order == nil {
+		return nil
+	}
+
+	edges := make([]Edge, 0, g.order.l.Len())
+	for e := g.order.l.Front(); e != nil; e = e.Next() {
+		k := e.Value.(edgeKey)
+		edges = append(edges, Edge{From: k.from, To: k.to, Weight: g.nodeTargets[k.from][k.to]})
+	}
+
+	return edges
+}
+
+func (g *graph) SelfLoops() []Edge {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	loops := make([]Edge, 0)
+	for id, tmap := range g.nodeTargets {
+		if wgt, ok := tmap[id]; ok {
+			loops = append(loops, Edge{From: id, To: id, Weight: wgt})
+		}
+	}
+	sortEdges(loops)
+
+	return loops
+}
+
+func (g *graph) HeaviestSource(id ID) (ID, float64, error) {
+	id = g.cfg.key(id)
+
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	if !g.unsafeIdExist(id) {
+		return nil, 0, fmt.Errorf("%s does not exist in graph", id)
+	}
+
+	sid, wgt, ok := heaviest(g.nodeSources[id])
+	if !ok {
+		return nil, 0, fmt.Errorf("%s has no sources", id)
+	}
+
+	return sid, wgt, nil
+}
+
+func (g *graph) HeaviestTarget(id ID) (ID, float64, error) {
+	id = g.cfg.key(id)
+
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	if !g.unsafeIdExist(id) {
+		return nil, 0, fmt.Errorf("%s does not exist in graph", id)
+	}
+
+	tid, wgt, ok := heaviest(g.nodeTargets[id])
+	if !ok {
+		return nil, 0, fmt.Errorf("%s has no targets", id)
+	}
+
+	return tid, wgt, nil
+}
+
+func (g *graph) SortedTargets(id ID) ([]Edge, error) {
+	id = g.cfg.key(id)
+
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	if !g.unsafeIdExist(id) {
+		return nil, fmt.Errorf("%s does not exist in graph", id)
+	}
+
+	edges := make([]Edge, 0, len(g.nodeTargets[id]))
+	for tid, wgt := range g.nodeTargets[id] {
+		edges = append(edges, Edge{From: id, To: tid, Weight: wgt})
+	}
+	sortEdges(edges)
+
+	return edges, nil
+}
+
+func (g *graph) SortedSources(id ID) ([]Edge, error) {
+	id = g.cfg.key(id)
+
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	if !g.unsafeIdExist(id) {
+		return nil, fmt.Errorf("%s does not exist in graph", id)
+	}
+
+	edges := make([]Edge, 0, len(g.nodeSources[id]))
+	for sid, wgt := range g.nodeSources[id] {
+		edges = append(edges, Edge{From: sid, To: id, Weight: wgt})
+	}
+	sortEdges(edges)
+
+	return edges, nil
+}
+
+func (g *graph) IncidentEdges(id ID) ([]Edge, error) {
+	id = g.cfg.key(id)
+
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	if !g.unsafeIdExist(id) {
+		return nil, fmt.Errorf("%s does not exist in graph", id)
+	}
+
+	edges := make([]Edge, 0, len(g.nodeTargets[id])+len(g.nodeSources[id]))
+	for tid, wgt := range g.nodeTargets[id] {
+		edges = append(edges, Edge{From: id, To: tid, Weight: wgt})
+	}
+
+	for sid, wgt := range g.nodeSources[id] {
+		// 自环已经在下游中记录过了
+		if sid != id {
+			edges = append(edges, Edge{From: sid, To: id, Weight: wgt})
+		}
+	}
+	sortEdges(edges)
+
+	return edges, nil
+}
+
+// 返回 m 中权重最大的 id，权重相同时取字符串最小的 id，保证结果稳定
+func heaviest(m map[ID]float64) (ID, float64, bool) {
+	var best ID
+	bestW := 0.0
+	for id, wgt := range m {
+		if best == nil || wgt > bestW || (wgt == bestW && id.String() < best.String()) {
+			best, bestW = id, wgt
+		}
+	}
+
+	return best, bestW, best != nil
+}
@@ -0,0 +1,148 @@
+package kraph
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestEdgesSortedByWeight(t *testing.T) {
+	g := buildGraph(
+		[]string{"a", "b", "c"},
+		[][2]string{{"b", "c"}, {"a", "c"}, {"a", "b"}, {"c", "a"}},
+		2, 1, 2, 3,
+	)
+
+	asc := g.EdgesSortedByWeight(false)
+	want := []string{"a->c", "a->b", "b->c", "c->a"}
+	for i, e := range asc {
+		if got := e.From.String() + "->" + e.To.String(); got != want[i] {
+			t.Errorf("ascending[%d] = %s, want %s", i, got, want[i])
+		}
+	}
+
+	desc := g.EdgesSortedByWeight(true)
+	want = []string{"c->a", "a->b", "b->c", "a->c"}
+	for i, e := range desc {
+		if got := e.From.String() + "->" + e.To.String(); got != want[i] {
+			t.Errorf("descending[%d] = %s, want %s", i, got, want[i])
+		}
+	}
+}
+
+func TestStreamEdges(t *testing.T) {
+	g := buildGraph(
+		[]string{"a", "b", "c"},
+		[][2]string{{"b", "c"}, {"a", "c"}, {"a", "b"}},
+		1, 2, 3,
+	)
+
+	got := make([]Edge, 0)
+	for e := range g.StreamEdges(context.Background()) {
+		got = append(got, e)
+	}
+	if len(got) != 3 || got[0].From.String() != "a" || got[0].To.String() != "b" || got[0].Weight != 3 {
+		t.Errorf("StreamEdges() = %v", got)
+	}
+
+	// 读取过程中可以修改图，不会死锁
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := g.StreamEdges(ctx)
+	<-ch
+	g.AddEdge(NewNid("a"), NewNid("c"), 1)
+	cancel()
+	for range ch {
+	}
+}
+
+func TestHeaviestSourceAndTarget(t *testing.T) {
+	g := buildGraph(
+		[]string{"a", "b", "c", "d"},
+		[][2]string{{"a", "d"}, {"b", "d"}, {"c", "d"}, {"d", "a"}, {"d", "b"}},
+		2, 5, 5, 1, 3,
+	)
+
+	if id, w, err := g.HeaviestSource(NewNid("d")); err != nil || id.String() != "b" || w != 5 {
+		t.Errorf("HeaviestSource(d) = %v, %v, %v, want b, 5", id, w, err)
+	}
+	if id, w, err := g.HeaviestTarget(NewNid("d")); err != nil || id.String() != "b" || w != 3 {
+		t.Errorf("HeaviestTarget(d) = %v, %v, %v, want b, 3", id, w, err)
+	}
+
+	if _, _, err := g.HeaviestSource(NewNid("c")); err == nil {
+		t.Error("expected error for node without sources")
+	}
+	if _, _, err := g.HeaviestTarget(NewNid("x")); err == nil {
+		t.Error("expected error for missing node")
+	}
+}
+
+func TestSelfLoops(t *testing.T) {
+	g := buildGraph(
+		[]string{"a", "b", "c"},
+		[][2]string{{"c", "c"}, {"a", "b"}, {"a", "a"}},
+		3, 1, 2,
+	)
+
+	want := []Edge{
+		{From: NewNid("a"), To: NewNid("a"), Weight: 2},
+		{From: NewNid("c"), To: NewNid("c"), Weight: 3},
+	}
+	if got := g.SelfLoops(); !reflect.DeepEqual(got, want) {
+		t.Errorf("SelfLoops() = %v, want %v", got, want)
+	}
+
+	if got := buildGraph([]string{"a", "b"}, [][2]string{{"a", "b"}}).SelfLoops(); len(got) != 0 {
+		t.Errorf("SelfLoops() = %v, want none", got)
+	}
+}
+
+func TestSortedTargetsAndSources(t *testing.T) {
+	g := buildGraph(
+		[]string{"a", "b", "c", "d"},
+		[][2]string{{"d", "c"}, {"d", "a"}, {"d", "b"}, {"c", "d"}, {"a", "d"}},
+		1, 2, 3, 4, 5,
+	)
+	a, b, c, d := NewNid("a"), NewNid("b"), NewNid("c"), NewNid("d")
+
+	targets, err := g.SortedTargets(d)
+	want := []Edge{{d, a, 2}, {d, b, 3}, {d, c, 1}}
+	if err != nil || !reflect.DeepEqual(targets, want) {
+		t.Errorf("SortedTargets(d) = %v, %v; want %v", targets, err, want)
+	}
+
+	sources, err := g.SortedSources(d)
+	want = []Edge{{a, d, 5}, {c, d, 4}}
+	if err != nil || !reflect.DeepEqual(sources, want) {
+		t.Errorf("SortedSources(d) = %v, %v; want %v", sources, err, want)
+	}
+
+	if got, err := g.SortedTargets(b); err != nil || len(got) != 0 {
+		t.Errorf("SortedTargets(b) = %v, %v; want none", got, err)
+	}
+	if _, err := g.SortedSources(NewNid("x")); err == nil {
+		t.Error("expected error for missing node")
+	}
+}
+
+func TestIncidentEdges(t *testing.T) {
+	g := buildGraph(
+		[]string{"a", "b", "c", "d"},
+		[][2]string{{"a", "b"}, {"b", "c"}, {"b", "b"}, {"d", "b"}, {"a", "c"}},
+		1, 2, 3, 4, 5,
+	)
+	a, b, c, d := NewNid("a"), NewNid("b"), NewNid("c"), NewNid("d")
+
+	want := []Edge{{a, b, 1}, {b, b, 3}, {b, c, 2}, {d, b, 4}}
+	if got, err := g.IncidentEdges(b); err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("IncidentEdges(b) = %v, %v; want %v", got, err, want)
+	}
+
+	g.AddNode(NewNode(NewNid("e")))
+	if got, err := g.IncidentEdges(NewNid("e")); err != nil || len(got) != 0 {
+		t.Errorf("IncidentEdges(e) = %v, %v; want none", got, err)
+	}
+	if _, err := g.IncidentEdges(NewNid("x")); err == nil {
+		t.Error("expected error for missing node")
+	}
+}
@@ -0,0 +1,171 @@
+package kraph
+
+import "fmt"
+
+func (g *graph) RemoveAndBypass(id ID) error {
+	return g.RemoveAndBypassWith(id, func(in, out float64) float64 {
+		return in + out
+	})
+}
+
+func (g *graph) RemoveAndBypassWith(id ID, combine func(in, out float64) float64) error {
+	id = g.cfg.key(id)
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if err := g.unsafeCheckFrozen(); err != nil {
+		return err
+	}
+
+	if !g.unsafeIdExist(id) {
+		return fmt.Errorf("%s does not exist in graph", id)
+	}
+
+	// 先计算并校验所有新边，避免校验失败时图处于修改了一半的状态
+	bypass := make([]Edge, 0)
+	for pred, in := range g.nodeSources[id] {
+		if pred == id {
+			continue
+		}
+
+		for succ, out := range g.nodeTargets[id] {
+			if succ == id || succ == pred {
+				continue
+			}
+
+			// pred 和 succ 都不是 id，已有的 pred -> succ 不受删除的影响，可以提前合并
+			wgt, err := g.unsafeMergedWeight(pred, succ, combine(in, out))
+			if err != nil {
+				return fmt.Errorf("edge %s -> %s: %w", pred, succ, err)
+			}
+			bypass = append(bypass, Edge{From: pred, To: succ, Weight: wgt})
+		}
+	}
+
+	g.unsafeDeleteNode(id)
+	for _, e := range bypass {
+		g.unsafeSetEdge(e.From, e.To, e.Weight)
+	}
+
+	return nil
+}
+
+func (g *graph) SplitNode(id, newID ID, assignTarget func(to ID) bool) error {
+	id, newID = g.cfg.key(id), g.cfg.key(newID)
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if err := g.unsafeCheckFrozen(); err != nil {
+		return err
+	}
+
+	if !g.unsafeIdExist(id) {
+		return fmt.Errorf("%s does not exist in graph", id)
+	}
+
+	if g.unsafeIdExist(newID) {
+		return fmt.Errorf("%s already exists in graph", newID)
+	}
+
+	if g.unsafeFull() {
+		return ErrCapacityExceeded
+	}
+
+	if err := g.cfg.validateWeight(1); err != nil {
+		return err
+	}
+
+	moved := make([]Edge, 0)
+	for to, wgt := range g.nodeTargets[id] {
+		if assignTarget(to) {
+			moved = append(moved, Edge{From: id, To: to, Weight: wgt})
+		}
+	}
+
+	g.unsafeAddNode(NewNode(newID))
+	for _, e := range moved {
+		val, hasVal := g.edgeVals[edgeKey{e.From, e.To}]
+		g.unsafeRemoveEdge(e.From, e.To)
+
+		// TypedGraph 边上的值跟随边一起移动
+		g.unsafeSetEdge(newID, e.To, e.Weight)
+		if hasVal {
+			g.edgeVals[edgeKey{newID, e.To}] = val
+		}
+	}
+	g.unsafeSetEdge(id, newID, 1)
+
+	return nil
+}
+
+func (g *graph) TransposeInPlace() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.unsafeCheckFrozen() != nil {
+		return
+	}
+
+	// 反转所有边不会改变强连通分量，也不会改变 node 的总度数，scc 缓存和 stats 都不需要更新
+	g.nodeSources, g.nodeTargets = g.nodeTargets, g.nodeSources
+	for _, d := range g.degrees {
+		d.in, d.out = d.out, d.in
+	}
+
+	if g.edgeVals != nil {
+		vals := make(map[edgeKey]interface{}, len(g.edgeVals))
+		for k, v := range g.edgeVals {
+			vals[edgeKey{k.to, k.from}] = v
+		}
+		g.edgeVals = vals
+	}
+	if g.order != nil {
+		g.order.reverse()
+	}
+}
+
+func (g *graph) ReverseEdge(from, to ID) error {
+	from, to = g.cfg.key(from), g.cfg.key(to)
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if err := g.unsafeCheckFrozen(); err != nil {
+		return err
+	}
+
+	if !g.unsafeIdExist(from) {
+		return fmt.Errorf("%s does not exist in graph", from)
+	}
+
+	if !g.unsafeIdExist(to) {
+		return fmt.Errorf("%s does not exist in graph", to)
+	}
+
+	wgt, ok := g.nodeTargets[from][to]
+	if !ok {
+		return fmt.Errorf("no edge from %s to %s", from, to)
+	}
+
+	// 自环反转后不变
+	if from == to {
+		return nil
+	}
+
+	merged, err := g.unsafeMergedWeight(to, from, wgt)
+	if err != nil {
+		return fmt.Errorf("edge %s -> %s: %w", to, from, err)
+	}
+
+	// TypedGraph 边上的值跟随边一起反转，覆盖 to -> from 上原有的值
+	val, hasVal := g.edgeVals[edgeKey{from, to}]
+	g.unsafeRemoveEdge(from, to)
+	g.unsafeSetEdge(to, from, merged)
+	if hasVal {
+		g.edgeVals[edgeKey{to, from}] = val
+	}
+
+	return nil
+}
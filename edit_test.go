@@ -0,0 +1,259 @@
+package kraph
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestRemoveAndBypass(t *testing.T) {
+	// a -> m -> c, b -> m -> c, m -> a, a -> c 已存在
+	g := buildGraph(
+		[]string{"a", "b", "c", "m"},
+		[][2]string{{"a", "m"}, {"b", "m"}, {"m", "c"}, {"m", "a"}, {"a", "c"}},
+		1.0, 2.0, 3.0, 4.0, 10.0,
+	)
+
+	if err := g.RemoveAndBypass(NewNid("m")); err != nil {
+		t.Fatal(err)
+	}
+
+	if g.GetNode(NewNid("m")) != nil {
+		t.Error("m should be removed")
+	}
+
+	cases := []struct {
+		from, to string
+		wgt      float64
+	}{
+		{"a", "c", 14.0},
+		{"b", "c", 5.0},
+		{"b", "a", 6.0},
+	}
+	for _, cs := range cases {
+		if w, err := g.GetWeight(NewNid(cs.to), NewNid(cs.from)); err != nil || w != cs.wgt {
+			t.Errorf("edge %s -> %s = %v, %v; want %v", cs.from, cs.to, w, err, cs.wgt)
+		}
+	}
+
+	// a -> m -> a 产生的自环应被丢弃
+	if _, err := g.GetWeight(NewNid("a"), NewNid("a")); err == nil {
+		t.Error("self-loop a -> a should not be created")
+	}
+
+	g2 := buildGraph([]string{"a", "m", "c"}, [][2]string{{"a", "m"}, {"m", "c"}}, 2.0, 5.0)
+	g2.RemoveAndBypassWith(NewNid("m"), func(in, out float64) float64 {
+		if in < out {
+			return in
+		}
+		return out
+	})
+	if w, _ := g2.GetWeight(NewNid("c"), NewNid("a")); w != 2.0 {
+		t.Errorf("combined edge a -> c = %v, want 2", w)
+	}
+
+	if err := g.RemoveAndBypass(NewNid("missing")); err == nil {
+		t.Error("expected error for missing node")
+	}
+}
+
+func TestReverseEdge(t *testing.T) {
+	g := buildGraph(
+		[]string{"a", "b", "c"},
+		[][2]string{{"a", "b"}, {"b", "c"}, {"c", "b"}},
+		2, 3, 4,
+	)
+	a, b, c := NewNid("a"), NewNid("b"), NewNid("c")
+
+	if err := g.ReverseEdge(a, b); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := g.GetWeight(b, a); err == nil {
+		t.Error("edge a -> b should be removed")
+	}
+	if w, err := g.GetWeight(a, b); err != nil || w != 2 {
+		t.Errorf("edge b -> a = %v, %v", w, err)
+	}
+
+	// 反向边已存在时权重相加
+	if err := g.ReverseEdge(b, c); err != nil {
+		t.Fatal(err)
+	}
+	if w, _ := g.GetWeight(b, c); w != 7 {
+		t.Errorf("edge c -> b = %v, want 7", w)
+	}
+
+	if err := g.ReverseEdge(a, c); err == nil {
+		t.Error("expected error for missing edge")
+	}
+	if err := g.ReverseEdge(a, NewNid("missing")); err == nil {
+		t.Error("expected error for missing node")
+	}
+}
+
+func TestTransposeInPlace(t *testing.T) {
+	a, b, c := NewNid("a"), NewNid("b"), NewNid("c")
+	fill := func(g Graph) Graph {
+		for _, id := range []ID{a, b, c} {
+			g.AddNode(NewNode(id))
+		}
+		g.AddEdge(b, a, 1)
+		g.AddEdge(c, b, 2)
+		g.AddEdge(c, c, 3)
+		g.AddEdge(c, a, 4)
+
+		return g
+	}
+
+	for _, g := range []Graph{fill(NewGraph()), fill(NewGraph(WithInsertionOrder()))} {
+		g.TransposeInPlace()
+
+		want := []Edge{{b, a, 1}, {c, a, 4}, {c, b, 2}, {c, c, 3}}
+		if got := g.EdgesSortedByWeight(false); len(got) != len(want) {
+			t.Fatalf("edges = %v, want %v", got, want)
+		}
+		for _, e := range want {
+			if w, err := g.EdgeWeight(e.From, e.To); err != nil || w != e.Weight {
+				t.Errorf("EdgeWeight(%s, %s) = %v, %v; want %v", e.From, e.To, w, err, e.Weight)
+			}
+		}
+		if _, ok := g.TargetWeight(a, b); ok {
+			t.Error("edge a -> b should have been reversed")
+		}
+
+		if in, _ := g.InDegree(a); in != 2 {
+			t.Errorf("InDegree(a) = %d, want 2", in)
+		}
+		if out, _ := g.OutDegree(c); out != 3 {
+			t.Errorf("OutDegree(c) = %d, want 3", out)
+		}
+		if srcs, _ := g.GetSources(b); len(srcs) != 1 || srcs[c] == nil {
+			t.Errorf("GetSources(b) = %v, want [c]", srcs)
+		}
+	}
+
+	g := NewGraph(WithInsertionOrder())
+	g.AddNode(NewNode(a))
+	g.AddNode(NewNode(b))
+	g.AddEdge(b, a, 1)
+	g.AddEdge(a, b, 2)
+	g.TransposeInPlace()
+	g.DeleteEdge(a, b)
+	if got := g.EdgesInOrder(); len(got) != 1 || got[0] != (Edge{a, b, 2}) {
+		t.Errorf("EdgesInOrder() = %v, want [a -> b]", got)
+	}
+
+	tg := NewTypedGraph[int, string]()
+	tg.AddNode(NewNode(a))
+	tg.AddNode(NewNode(b))
+	tg.AddEdgeValue(a, b, 1, "ab")
+	tg.TransposeInPlace()
+	if v, ok := tg.GetEdgeValue(b, a); !ok || v != "ab" {
+		t.Errorf("GetEdgeValue(b, a) = %q, %v; want ab", v, ok)
+	}
+}
+
+func TestSplitNode(t *testing.T) {
+	g := buildGraph(
+		[]string{"a", "b", "c", "s", "m"},
+		[][2]string{{"s", "m"}, {"m", "a"}, {"m", "b"}, {"m", "c"}, {"m", "m"}},
+		1, 2, 3, 4, 5,
+	)
+	m, n := NewNid("m"), NewNid("n")
+
+	moveTo := map[string]bool{"b": true, "c": true, "m": true}
+	if err := g.SplitNode(m, n, func(to ID) bool { return moveTo[to.String()] }); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []Edge{
+		{m, NewNid("a"), 2},
+		{m, n, 1},
+		{n, NewNid("b"), 3},
+		{n, NewNid("c"), 4},
+		{n, m, 5},
+		{NewNid("s"), m, 1},
+	}
+	got := g.EdgesSortedByWeight(false)
+	sortEdges(got)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("edges after SplitNode = %v, want %v", got, want)
+	}
+
+	all := func(ID) bool { return true }
+	if err := g.SplitNode(m, n, all); err == nil {
+		t.Error("expected error for existing newID")
+	}
+	if err := g.SplitNode(NewNid("x"), NewNid("y"), all); err == nil {
+		t.Error("expected error for missing node")
+	}
+
+	tg := NewTypedGraph[int, string]()
+	tg.AddNode(NewNode(m))
+	tg.AddNode(NewNode(NewNid("a")))
+	tg.AddEdgeValue(m, NewNid("a"), 2, "keep")
+	tg.SplitNode(m, n, all)
+	if v, ok := tg.GetEdgeValue(n, NewNid("a")); !ok || v != "keep" {
+		t.Errorf("moved edge value = %q, %v; want keep", v, ok)
+	}
+}
+
+func TestRemoveAndBypassValidatesMergedWeight(t *testing.T) {
+	errTooHeavy := errors.New("too heavy")
+	g := NewGraph(WithWeightValidator(func(w float64) error {
+		if w > 10 {
+			return errTooHeavy
+		}
+		return nil
+	}))
+	a, m, c := NewNid("a"), NewNid("m"), NewNid("c")
+	for _, id := range []ID{a, m, c} {
+		g.AddNode(NewNode(id))
+	}
+	g.AddEdge(m, a, 3)
+	g.AddEdge(c, m, 4)
+	g.AddEdge(c, a, 8)
+
+	// 3 + 4 本身合法，但与已有的 a -> c 合并后为 15
+	if err := g.RemoveAndBypass(m); !errors.Is(err, errTooHeavy) {
+		t.Errorf("RemoveAndBypass = %v, want errTooHeavy", err)
+	}
+	if g.GetNode(m) == nil {
+		t.Error("graph should be unchanged after a failed bypass")
+	}
+	if w, _ := g.EdgeWeight(a, c); w != 8 {
+		t.Errorf("EdgeWeight(a, c) = %v, want 8", w)
+	}
+
+	big := buildGraph([]string{"a", "m", "c"}, [][2]string{{"a", "m"}, {"m", "c"}, {"a", "c"}}, 1, 1, math.MaxFloat64)
+	if err := big.RemoveAndBypassWith(m, func(in, out float64) float64 { return math.MaxFloat64 }); !errors.Is(err, ErrNonFiniteWeight) {
+		t.Errorf("RemoveAndBypassWith overflow = %v, want ErrNonFiniteWeight", err)
+	}
+}
+
+func TestReverseEdgeValidatesAndKeepsValue(t *testing.T) {
+	a, b := NewNid("a"), NewNid("b")
+
+	g := buildGraph([]string{"a", "b"}, [][2]string{{"a", "b"}, {"b", "a"}}, math.MaxFloat64, math.MaxFloat64)
+	if err := g.ReverseEdge(a, b); !errors.Is(err, ErrNonFiniteWeight) {
+		t.Errorf("ReverseEdge overflow = %v, want ErrNonFiniteWeight", err)
+	}
+	if _, ok := g.TargetWeight(a, b); !ok {
+		t.Error("a -> b should survive a failed ReverseEdge")
+	}
+
+	tg := NewTypedGraph[int, string]()
+	tg.AddNode(NewNode(a))
+	tg.AddNode(NewNode(b))
+	tg.AddEdgeValue(a, b, 2, "ab")
+	if err := tg.ReverseEdge(a, b); err != nil {
+		t.Fatal(err)
+	}
+	if v, ok := tg.GetEdgeValue(b, a); !ok || v != "ab" {
+		t.Errorf("GetEdgeValue(b, a) = %q, %v; want ab", v, ok)
+	}
+	if _, ok := tg.GetEdgeValue(a, b); ok {
+		t.Error("a -> b should have no value after reversal")
+	}
+}
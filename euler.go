@@ -0,0 +1,89 @@
+package kraph
+
+import (
+	"errors"
+	"fmt"
+)
+
+func (g *graph) EulerianPath() ([]ID, error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	ids := g.unsafeNodeIDs()
+	edges := 0
+	var start, end ID
+	for _, id := range ids {
+		out, in := len(g.nodeTargets[id]), len(g.nodeSources[id])
+		edges += out
+
+		switch out - in {
+		case 0:
+		case 1:
+			if start != nil {
+				return nil, fmt.Errorf("no eulerian path: both %s and %s have one more outgoing edge than incoming", start, id)
+			}
+			start = id
+		case -1:
+			if end != nil {
+				return nil, fmt.Errorf("no eulerian path: both %s and %s have one more incoming edge than outgoing", end, id)
+			}
+			end = id
+		default:
+			return nil, fmt.Errorf("no eulerian path: in-degree and out-degree of %s differ by more than one", id)
+		}
+	}
+
+	if edges == 0 {
+		return []ID{}, nil
+	}
+
+	if (start == nil) != (end == nil) {
+		return nil, errors.New("no eulerian path: unbalanced degrees")
+	}
+
+	// 如果所有 node 出入度相等，则从 id 最小的有出边的 node 开始得到欧拉回路
+	if start == nil {
+		for _, id := range ids {
+			if len(g.nodeTargets[id]) > 0 {
+				start = id
+				break
+			}
+		}
+	}
+
+	adj := make(map[ID][]ID, len(ids))
+	for _, id := range ids {
+		ts := make([]ID, 0, len(g.nodeTargets[id]))
+		for tid := range g.nodeTargets[id] {
+			ts = append(ts, tid)
+		}
+		sortIDs(ts)
+		adj[id] = ts
+	}
+
+	// 迭代实现的 Hierholzer 算法，next 记录每个 node 下一条未使用的边
+	next := make(map[ID]int, len(ids))
+	stack := []ID{start}
+	path := make([]ID, 0, edges+1)
+	for len(stack) > 0 {
+		v := stack[len(stack)-1]
+		if next[v] < len(adj[v]) {
+			stack = append(stack, adj[v][next[v]])
+			next[v]++
+		} else {
+			path = append(path, v)
+			stack = stack[:len(stack)-1]
+		}
+	}
+
+	// 有边没有被访问到，说明这些边不连通
+	if len(path) != edges+1 {
+		return nil, errors.New("no eulerian path: edges are not connected")
+	}
+
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+
+	return path, nil
+}
@@ -0,0 +1,57 @@
+package kraph
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestEulerianPath(t *testing.T) {
+	// a -> b -> c -> a -> d，a 的出度比入度多 1，d 的入度比出度多 1
+	g := buildGraph(
+		[]string{"a", "b", "c", "d"},
+		[][2]string{{"a", "b"}, {"b", "c"}, {"c", "a"}, {"a", "d"}},
+	)
+
+	path, err := g.EulerianPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := idStrings(path), []string{"a", "b", "c", "a", "d"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("EulerianPath() = %v, want %v", got, want)
+	}
+
+	// 欧拉回路
+	circuit := buildGraph(
+		[]string{"a", "b", "c"},
+		[][2]string{{"a", "b"}, {"b", "c"}, {"c", "a"}, {"a", "c"}, {"c", "b"}, {"b", "a"}},
+	)
+	path, err = circuit.EulerianPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(path) != 7 || path[0] != path[6] {
+		t.Errorf("EulerianPath() on circuit = %v", idStrings(path))
+	}
+	used := make(map[[2]string]bool)
+	for i := 1; i < len(path); i++ {
+		e := [2]string{path[i-1].String(), path[i].String()}
+		if used[e] {
+			t.Errorf("edge %v traversed twice", e)
+		}
+		used[e] = true
+	}
+
+	bad := buildGraph([]string{"a", "b", "c"}, [][2]string{{"a", "b"}, {"a", "c"}})
+	if _, err := bad.EulerianPath(); err == nil {
+		t.Error("expected error when degree condition fails")
+	}
+
+	// 两个互不相连的环满足度数条件，但并不连通
+	split := buildGraph(
+		[]string{"a", "b", "c", "d"},
+		[][2]string{{"a", "b"}, {"b", "a"}, {"c", "d"}, {"d", "c"}},
+	)
+	if _, err := split.EulerianPath(); err == nil {
+		t.Error("expected error for disconnected edges")
+	}
+}
@@ -0,0 +1,33 @@
+package kraph
+
+import "errors"
+
+// ErrFrozen 表示 graph 已经被 Freeze 冻结，不能再修改
+var ErrFrozen = errors.New("graph is frozen")
+
+func (g *graph) Freeze() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.frozen = true
+}
+
+func (g *graph) IsFrozen() bool {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	return g.frozen
+}
+
+// graph 已经冻结时返回 ErrFrozen，设置了 WithFrozenPanic 时直接 panic，调用方需要持有写锁
+func (g *graph) unsafeCheckFrozen() error {
+	if !g.frozen {
+		return nil
+	}
+
+	if g.cfg.panicOnFrozen {
+		panic(ErrFrozen)
+	}
+
+	return ErrFrozen
+}
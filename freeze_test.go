@@ -0,0 +1,64 @@
+package kraph
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestFreeze(t *testing.T) {
+	g := buildGraph([]string{"a", "b"}, [][2]string{{"a", "b"}}, 2)
+	a, b, c := NewNid("a"), NewNid("b"), NewNid("c")
+
+	g.Freeze()
+	if !g.IsFrozen() {
+		t.Fatal("graph should be frozen")
+	}
+
+	if err := g.AddEdge(a, b, 1); !errors.Is(err, ErrFrozen) {
+		t.Errorf("AddEdge: got %v, want ErrFrozen", err)
+	}
+	if err := g.DeleteEdge(b, a); !errors.Is(err, ErrFrozen) {
+		t.Errorf("DeleteEdge: got %v, want ErrFrozen", err)
+	}
+	if err := g.ScaleWeights(func(w float64) float64 { return w * 2 }); !errors.Is(err, ErrFrozen) {
+		t.Errorf("ScaleWeights: got %v, want ErrFrozen", err)
+	}
+	if g.AddNode(NewNode(c)) {
+		t.Error("AddNode should fail on a frozen graph")
+	}
+	if g.DeleteNode(a) {
+		t.Error("DeleteNode should fail on a frozen graph")
+	}
+	g.Init()
+	g.NormalizeOutWeights()
+
+	// 读操作不受影响，图的内容保持不变
+	if g.GetNodeCount() != 2 {
+		t.Errorf("node count = %d, want 2", g.GetNodeCount())
+	}
+	if w, err := g.EdgeWeight(a, b); err != nil || w != 2 {
+		t.Errorf("EdgeWeight(a, b) = %v, %v; want 2", w, err)
+	}
+
+	// 复制出来的图不会被冻结
+	cp := NewGraph()
+	if err := cp.ReplaceContents(g); err != nil {
+		t.Fatal(err)
+	}
+	if err := cp.AddEdge(a, b, 1); err != nil {
+		t.Errorf("copy should be mutable: %v", err)
+	}
+}
+
+func TestWithFrozenPanic(t *testing.T) {
+	g := NewGraph(WithFrozenPanic())
+	g.Freeze()
+
+	defer func() {
+		if r := recover(); r != ErrFrozen {
+			t.Errorf("recovered %v, want ErrFrozen", r)
+		}
+	}()
+	g.AddNode(NewNode(NewNid("a")))
+	t.Error("AddNode should panic")
+}
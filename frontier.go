@@ -0,0 +1,100 @@
+package kraph
+
+import "container/heap"
+
+// Frontier 是以 id 为索引的最小优先队列，每个 id 最多出现一次，可以在 O(log n) 内修改已有 id 的优先级
+// 零值可以直接使用，Frontier 不是并发安全的
+type Frontier struct {
+	h frontierHeap
+}
+
+// NewFrontier 创建一个空的 Frontier
+func NewFrontier() *Frontier {
+	return &Frontier{}
+}
+
+// Len 返回 Frontier 中 id 的数量
+func (f *Frontier) Len() int {
+	return len(f.h.items)
+}
+
+// Push 将 id 以 priority 加入 Frontier，如果 id 已经存在则与 Update 相同
+func (f *Frontier) Push(id ID, priority float64) {
+	f.Update(id, priority)
+}
+
+// Update 将 id 的优先级修改为 priority（可以增大也可以减小），如果 id 不存在则将其加入
+func (f *Frontier) Update(id ID, priority float64) {
+	if i, ok := f.h.index[id]; ok {
+		f.h.items[i].priority = priority
+		heap.Fix(&f.h, i)
+
+		return
+	}
+
+	if f.h.index == nil {
+		f.h.index = make(map[ID]int)
+	}
+	heap.Push(&f.h, frontierItem{id: id, priority: priority})
+}
+
+// Pop 取出并返回优先级最小的 id 及其优先级，Frontier 为空时返回 false
+func (f *Frontier) Pop() (ID, float64, bool) {
+	if len(f.h.items) == 0 {
+		return nil, 0, false
+	}
+
+	it := heap.Pop(&f.h).(frontierItem)
+
+	return it.id, it.priority, true
+}
+
+// Peek 返回优先级最小的 id 及其优先级但不取出，Frontier 为空时返回 false
+func (f *Frontier) Peek() (ID, float64, bool) {
+	if len(f.h.items) == 0 {
+		return nil, 0, false
+	}
+
+	it := f.h.items[0]
+
+	return it.id, it.priority, true
+}
+
+// Contains 判断 id 是否在 Frontier 中
+func (f *Frontier) Contains(id ID) bool {
+	_, ok := f.h.index[id]
+
+	return ok
+}
+
+type frontierItem struct {
+	id       ID
+	priority float64
+}
+
+// 实现 heap.Interface，index 记录每个 id 在 items 中的位置
+type frontierHeap struct {
+	items []frontierItem
+	index map[ID]int
+}
+
+func (h frontierHeap) Len() int           { return len(h.items) }
+func (h frontierHeap) Less(i, j int) bool { return h.items[i].priority < h.items[j].priority }
+func (h frontierHeap) Swap(i, j int) {
+	h.items[i], h.items[j] = h.items[j], h.items[i]
+	h.index[h.items[i].id] = i
+	h.index[h.items[j].id] = j
+}
+func (h *frontierHeap) Push(x interface{}) {
+	it := x.(frontierItem)
+	h.index[it.id] = len(h.items)
+	h.items = append(h.items, it)
+}
+func (h *frontierHeap) Pop() interface{} {
+	n := len(h.items)
+	it := h.items[n-1]
+	h.items = h.items[:n-1]
+	delete(h.index, it.id)
+
+	return it
+}
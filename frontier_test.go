@@ -0,0 +1,51 @@
+package kraph
+
+import "testing"
+
+func TestFrontier(t *testing.T) {
+	f := NewFrontier()
+	if _, _, ok := f.Pop(); ok {
+		t.Error("Pop on empty frontier returned true")
+	}
+
+	a, b, c, d := NewNid("a"), NewNid("b"), NewNid("c"), NewNid("d")
+	f.Push(a, 5)
+	f.Push(b, 3)
+	f.Push(c, 4)
+	f.Push(d, 1)
+
+	// 减小、增大优先级，以及对已有 id 再次 Push
+	f.Update(a, 2)
+	f.Update(d, 6)
+	f.Push(c, 0)
+
+	if f.Len() != 4 {
+		t.Errorf("Len() = %d, want 4", f.Len())
+	}
+	if id, p, ok := f.Peek(); !ok || id != c || p != 0 {
+		t.Errorf("Peek() = %v, %v, %v", id, p, ok)
+	}
+
+	want := []struct {
+		id ID
+		p  float64
+	}{{c, 0}, {a, 2}, {b, 3}, {d, 6}}
+	for _, w := range want {
+		id, p, ok := f.Pop()
+		if !ok || id != w.id || p != w.p {
+			t.Errorf("Pop() = %v, %v, %v; want %v, %v", id, p, ok, w.id, w.p)
+		}
+		if f.Contains(id) {
+			t.Errorf("%s still in frontier after Pop", id)
+		}
+	}
+
+	// 零值可以直接使用，取出后可以再次加入
+	var z Frontier
+	z.Update(a, 1)
+	z.Pop()
+	z.Push(a, 2)
+	if id, p, ok := z.Pop(); !ok || id != a || p != 2 {
+		t.Errorf("zero value Pop() = %v, %v, %v", id, p, ok)
+	}
+}
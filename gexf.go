@@ -0,0 +1,37 @@
+package kraph
+
+import (
+	"bufio"
+	"encoding/xml"
+	"io"
+	"strconv"
+)
+
+func (g *graph) GEXF(w io.Writer) error {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	bw := bufio.NewWriter(w)
+
+	bw.WriteString(xml.Header)
+	bw.WriteString(`<gexf xmlns="http://gexf.net/1.3" version="1.3">` + "\n")
+	bw.WriteString(`  <graph mode="static" defaultedgetype="directed">` + "\n")
+
+	bw.WriteString("    <nodes>\n")
+	for _, id := range g.unsafeNodeIDs() {
+		s := xmlEscape(id.String())
+		bw.WriteString(`      <node id="` + s + `" label="` + s + `"/>` + "\n")
+	}
+	bw.WriteString("    </nodes>\n")
+
+	bw.WriteString("    <edges>\n")
+	for i, e := range g.unsafeEdges() {
+		bw.WriteString(`      <edge id="` + strconv.Itoa(i) + `" source="` + xmlEscape(e.From.String()) +
+			`" target="` + xmlEscape(e.To.String()) + `" weight="` + strconv.FormatFloat(e.Weight, 'g', -1, 64) + `"/>` + "\n")
+	}
+	bw.WriteString("    </edges>\n")
+
+	bw.WriteString("  </graph>\n</gexf>\n")
+
+	return bw.Flush()
+}
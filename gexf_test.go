@@ -0,0 +1,53 @@
+package kraph
+
+import (
+	"bytes"
+	"encoding/xml"
+	"testing"
+)
+
+func TestGEXF(t *testing.T) {
+	g := NewGraph()
+
+	a, b, c := NewNid("a"), NewNid("b"), NewNid("c&d")
+	g.AddNode(NewNode(a))
+	g.AddNode(NewNode(b))
+	g.AddNode(NewNode(c))
+	g.AddEdge(b, a, 1.5)
+	g.AddEdge(c, b, 3)
+
+	var buf bytes.Buffer
+	if err := g.GEXF(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	var doc struct {
+		Graph struct {
+			EdgeType string `xml:"defaultedgetype,attr"`
+			Nodes    []struct {
+				ID string `xml:"id,attr"`
+			} `xml:"nodes>node"`
+			Edges []struct {
+				Source string  `xml:"source,attr"`
+				Target string  `xml:"target,attr"`
+				Weight float64 `xml:"weight,attr"`
+			} `xml:"edges>edge"`
+		} `xml:"graph"`
+	}
+	if err := xml.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("invalid xml: %v\n%s", err, buf.String())
+	}
+
+	if doc.Graph.EdgeType != "directed" {
+		t.Errorf("defaultedgetype = %q", doc.Graph.EdgeType)
+	}
+	if len(doc.Graph.Nodes) != 3 || doc.Graph.Nodes[2].ID != "c&d" {
+		t.Errorf("nodes = %v", doc.Graph.Nodes)
+	}
+	if len(doc.Graph.Edges) != 2 {
+		t.Fatalf("edges = %v", doc.Graph.Edges)
+	}
+	if e := doc.Graph.Edges[1]; e.Source != "b" || e.Target != "c&d" || e.Weight != 3 {
+		t.Errorf("edge = %v", e)
+	}
+}
@@ -0,0 +1,141 @@
+package kraph
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+func (g *graph) GraphML(w io.Writer) error {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	bw := bufio.NewWriter(w)
+
+	bw.WriteString(xml.Header)
+	bw.WriteString(`<graphml xmlns="http://graphml.graphdrawing.org/xmlns">` + "\n")
+	bw.WriteString(`  <key id="weight" for="edge" attr.name="weight" attr.type="double"/>` + "\n")
+	bw.WriteString(`  <graph id="G" edgedefault="directed">` + "\n")
+
+	for _, id := range g.unsafeNodeIDs() {
+		bw.WriteString(`    <node id="` + xmlEscape(id.String()) + `"/>` + "\n")
+	}
+
+	for _, e := range g.unsafeEdges() {
+		bw.WriteString(`    <edge source="` + xmlEscape(e.From.String()) + `" target="` + xmlEscape(e.To.String()) + `">`)
+		bw.WriteString(`<data key="weight">` + strconv.FormatFloat(e.Weight, 'g', -1, 64) + `</data></edge>` + "\n")
+	}
+
+	bw.WriteString("  </graph>\n</graphml>\n")
+
+	return bw.Flush()
+}
+
+func xmlEscape(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+
+	return b.String()
+}
+
+type graphmlDoc struct {
+	Keys   []graphmlKey   `xml:"key"`
+	Graphs []graphmlGraph `xml:"graph"`
+}
+
+type graphmlKey struct {
+	ID      string `xml:"id,attr"`
+	For     string `xml:"for,attr"`
+	Name    string `xml:"attr.name,attr"`
+	Default string `xml:"default"`
+}
+
+type graphmlGraph struct {
+	EdgeDefault string        `xml:"edgedefault,attr"`
+	Nodes       []graphmlNode `xml:"node"`
+	Edges       []graphmlEdge `xml:"edge"`
+}
+
+type graphmlNode struct {
+	ID string `xml:"id,attr"`
+}
+
+type graphmlEdge struct {
+	Source   string        `xml:"source,attr"`
+	Target   string        `xml:"target,attr"`
+	Directed string        `xml:"directed,attr"`
+	Data     []graphmlData `xml:"data"`
+}
+
+type graphmlData struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+// LoadGraphML 从 GraphML 文档中读取 node、边以及名为 weight 的边属性并构建 graph
+// 无向边会被拆分成两条方向相反的有向边，没有权重的边权重为 1，其它属性会被忽略
+func LoadGraphML(r io.Reader, opts ...Option) (Graph, error) {
+	var doc graphmlDoc
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, err
+	}
+
+	// 找到声明为 weight 的边属性及其默认值
+	weightKey := ""
+	defaultWgt := 1.0
+	for _, k := range doc.Keys {
+		if k.Name == "weight" && (k.For == "edge" || k.For == "all" || k.For == "") {
+			weightKey = k.ID
+			if d := strings.TrimSpace(k.Default); d != "" {
+				wgt, err := strconv.ParseFloat(d, 64)
+				if err != nil {
+					return nil, fmt.Errorf("invalid default weight %q: %v", d, err)
+				}
+				defaultWgt = wgt
+			}
+		}
+	}
+
+	g := NewGraph(opts...)
+	for _, gr := range doc.Graphs {
+		for _, n := range gr.Nodes {
+			g.AddNode(NewNode(NewNid(n.ID)))
+		}
+
+		for _, e := range gr.Edges {
+			wgt := defaultWgt
+			for _, d := range e.Data {
+				if weightKey != "" && d.Key == weightKey {
+					v, err := strconv.ParseFloat(strings.TrimSpace(d.Value), 64)
+					if err != nil {
+						return nil, fmt.Errorf("invalid weight %q on edge %s -> %s: %v", d.Value, e.Source, e.Target, err)
+					}
+					wgt = v
+				}
+			}
+
+			from, to := NewNid(e.Source), NewNid(e.Target)
+			g.AddNode(NewNode(from))
+			g.AddNode(NewNode(to))
+
+			directed := gr.EdgeDefault != "undirected"
+			if e.Directed != "" {
+				directed = e.Directed == "true"
+			}
+
+			if err := g.AddEdge(to, from, wgt); err != nil {
+				return nil, err
+			}
+			if !directed && from != to {
+				if err := g.AddEdge(from, to, wgt); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	return g, nil
+}
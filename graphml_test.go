@@ -0,0 +1,109 @@
+package kraph
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+func TestGraphML(t *testing.T) {
+	g := NewGraph()
+
+	a, b := NewNid(`a&"x"`), NewNid("b<1>")
+	g.AddNode(NewNode(a))
+	g.AddNode(NewNode(b))
+	g.AddEdge(b, a, 2.5)
+
+	var buf bytes.Buffer
+	if err := g.GraphML(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	var doc struct {
+		Keys []struct {
+			ID string `xml:"id,attr"`
+		} `xml:"key"`
+		Graph struct {
+			Nodes []struct {
+				ID string `xml:"id,attr"`
+			} `xml:"node"`
+			Edges []struct {
+				Source string `xml:"source,attr"`
+				Target string `xml:"target,attr"`
+				Data   string `xml:"data"`
+			} `xml:"edge"`
+		} `xml:"graph"`
+	}
+	if err := xml.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("invalid xml: %v\n%s", err, buf.String())
+	}
+
+	if len(doc.Keys) != 1 || doc.Keys[0].ID != "weight" {
+		t.Errorf("keys = %v", doc.Keys)
+	}
+	if len(doc.Graph.Nodes) != 2 || doc.Graph.Nodes[0].ID != a.String() {
+		t.Errorf("nodes = %v", doc.Graph.Nodes)
+	}
+	if len(doc.Graph.Edges) != 1 {
+		t.Fatalf("edges = %v", doc.Graph.Edges)
+	}
+	e := doc.Graph.Edges[0]
+	if e.Source != a.String() || e.Target != b.String() || strings.TrimSpace(e.Data) != "2.5" {
+		t.Errorf("edge = %v", e)
+	}
+}
+
+func TestLoadGraphML(t *testing.T) {
+	doc := `<?xml version="1.0" encoding="UTF-8"?>
+<graphml xmlns="http://graphml.graphdrawing.org/xmlns">
+  <key id="d0" for="edge" attr.name="weight" attr.type="double"/>
+  <key id="d1" for="node" attr.name="color" attr.type="string"/>
+  <graph id="G" edgedefault="directed">
+    <node id="a"><data key="d1">red</data></node>
+    <node id="b"/>
+    <node id="c"/>
+    <edge source="a" target="b"><data key="d0">1.5</data></edge>
+    <edge source="b" target="c" directed="false"><data key="d0">2</data></edge>
+    <edge source="c" target="a"/>
+  </graph>
+</graphml>`
+
+	g, err := LoadGraphML(strings.NewReader(doc))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if g.GetNodeCount() != 3 {
+		t.Errorf("node count = %d", g.GetNodeCount())
+	}
+
+	a, b, c := NewNid("a"), NewNid("b"), NewNid("c")
+	cases := []struct {
+		from, to ID
+		wgt      float64
+	}{
+		{a, b, 1.5},
+		{b, c, 2},
+		{c, b, 2},
+		{c, a, 1},
+	}
+	for _, cs := range cases {
+		if w, err := g.GetWeight(cs.to, cs.from); err != nil || w != cs.wgt {
+			t.Errorf("edge %s -> %s = %v, %v; want %v", cs.from, cs.to, w, err, cs.wgt)
+		}
+	}
+
+	// 导出后再导入应得到相同的图
+	var buf bytes.Buffer
+	g.GraphML(&buf)
+	g2, err := LoadGraphML(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, cs := range cases {
+		if w, _ := g2.GetWeight(cs.to, cs.from); w != cs.wgt {
+			t.Errorf("round trip edge %s -> %s = %v; want %v", cs.from, cs.to, w, cs.wgt)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package kraph
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"github.com/pquerna/ffjson/ffjson"
+	"io"
+	"math"
+)
+
+// WriteJSON 每写入这么多条边就 flush 一次
+const jsonFlushEvery = 1024
+
+type jsonEdge struct {
+	From   string  `json:"from"`
+	To     string  `json:"to"`
+	Weight float64 `json:"weight"`
+}
+
+func (g *graph) WriteJSON(w io.Writer) error {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	// JSON 无法表示 NaN 和无穷大，需要在写入任何内容之前检查，避免 w 中留下不完整的 json
+	for pid, tmap := range g.nodeTargets {
+		for id, wgt := range tmap {
+			if math.IsNaN(wgt) || math.IsInf(wgt, 0) {
+				return fmt.Errorf("edge %s -> %s: %w: got %v", pid, id, ErrNonFiniteWeight, wgt)
+			}
+		}
+	}
+
+	bw := bufio.NewWriter(w)
+	enc := json.NewEncoder(bw)
+
+	bw.WriteString(`{"nodes":[`)
+	first := true
+	for id := range g.nodeList {
+		if !first {
+			bw.WriteByte(',')
+		}
+		first = false
+
+		if err := enc.Encode(id.String()); err != nil {
+			return err
+		}
+	}
+
+	bw.WriteString(`],"edges":[`)
+	first = true
+	count := 0
+	for pid, tmap := range g.nodeTargets {
+		for id, wgt := range tmap {
+			if !first {
+				bw.WriteByte(',')
+			}
+			first = false
+
+			if err := enc.Encode(jsonEdge{From: pid.String(), To: id.String(), Weight: wgt}); err != nil {
+				return err
+			}
+
+			count++
+			if count%jsonFlushEvery == 0 {
+				if err := bw.Flush(); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	bw.WriteString(`]}`)
+
+	return bw.Flush()
+}
+
+// 构建 JSON 的输出内容：下游 id -> 上游 id -> 权重
+func (g *graph) unsafeJSONMap() map[string]map[string]float64 {
+	rs := make(map[string]map[string]float64, len(g.nodeSources))
+	for id, smap := range g.nodeSources {
+		if len(smap) == 0 {
+			continue
+		}
+
+		m := make(map[string]float64, len(smap))
+		for pid, wgt := range smap {
+			m[pid.String()] = wgt
+		}
+		rs[id.String()] = m
+	}
+
+	return rs
+}
+
+func (g *graph) JSONSnapshot() ([]byte, error) {
+	g.mu.RLock()
+	rs := g.unsafeJSONMap()
+	g.mu.RUnlock()
+
+	return ffjson.Marshal(rs)
+}
+
+type jsonOrderedDoc struct {
+	Nodes []string   `json:"nodes"`
+	Edges []jsonEdge `json:"edges"`
+}
+
+func (g *graph) JSONOrdered() ([]byte, error) {
+	g.mu.RLock()
+	ids := g.unsafeNodeIDs()
+	edges := g.unsafeEdges()
+	g.mu.RUnlock()
+
+	doc := jsonOrderedDoc{
+		Nodes: make([]string, 0, len(ids)),
+		Edges: make([]jsonEdge, 0, len(edges)),
+	}
+	for _, id := range ids {
+		doc.Nodes = append(doc.Nodes, id.String())
+	}
+	for _, e := range edges {
+		doc.Edges = append(doc.Edges, jsonEdge{From: e.From.String(), To: e.To.String(), Weight: e.Weight})
+	}
+
+	return ffjson.Marshal(doc)
+}
@@ -0,0 +1,148 @@
+package kraph
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestWriteJSON(t *testing.T) {
+	g := NewGraph()
+
+	a, b, c := NewNid("a"), NewNid("b"), NewNid("c")
+	g.AddNode(NewNode(a))
+	g.AddNode(NewNode(b))
+	g.AddNode(NewNode(c))
+	g.AddEdge(b, a, 1.5)
+	g.AddEdge(c, a, 2.0)
+
+	var buf bytes.Buffer
+	if err := g.WriteJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	var out struct {
+		Nodes []string   `json:"nodes"`
+		Edges []jsonEdge `json:"edges"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+		t.Fatalf("invalid json %q: %v", buf.String(), err)
+	}
+
+	if len(out.Nodes) != 3 {
+		t.Errorf("nodes = %v", out.Nodes)
+	}
+
+	got := make(map[string]float64)
+	for _, e := range out.Edges {
+		got[e.From+"->"+e.To] = e.Weight
+	}
+	if len(got) != 2 || got["a->b"] != 1.5 || got["a->c"] != 2.0 {
+		t.Errorf("edges = %v", out.Edges)
+	}
+}
+
+func TestWriteJSONNonFinite(t *testing.T) {
+	g := NewGraph(WithNonFiniteWeights())
+	a, b := NewNid("a"), NewNid("b")
+	g.AddNode(NewNode(a))
+	g.AddNode(NewNode(b))
+	g.AddEdge(b, a, math.Inf(1))
+
+	var buf bytes.Buffer
+	if err := g.WriteJSON(&buf); !errors.Is(err, ErrNonFiniteWeight) {
+		t.Errorf("WriteJSON = %v, want ErrNonFiniteWeight", err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("WriteJSON wrote %q before failing", buf.String())
+	}
+}
+
+func TestJSONSnapshot(t *testing.T) {
+	g := NewGraph()
+
+	a, b, c := NewNid("a"), NewNid("b"), NewNid("c")
+	g.AddNode(NewNode(a))
+	g.AddNode(NewNode(b))
+	g.AddNode(NewNode(c))
+	g.AddEdge(c, a, 1.5)
+	g.AddEdge(c, b, 2.0)
+
+	j, err := g.JSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	snap, err := g.JSONSnapshot()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(j, snap) {
+		t.Errorf("JSONSnapshot() = %s, JSON() = %s", snap, j)
+	}
+
+	var out map[string]map[string]float64
+	if err := json.Unmarshal(snap, &out); err != nil {
+		t.Fatal(err)
+	}
+	if len(out) != 1 || out["c"]["a"] != 1.5 || out["c"]["b"] != 2.0 {
+		t.Errorf("JSONSnapshot() = %s", snap)
+	}
+}
+
+func TestJSONOrdered(t *testing.T) {
+	g := buildGraph(
+		[]string{"c", "a", "b"},
+		[][2]string{{"c", "a"}, {"a", "c"}, {"a", "b"}},
+		3, 2, 1.5,
+	)
+
+	got, err := g.JSONOrdered()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `{"nodes":["a","b","c"],"edges":[` +
+		`{"from":"a","to":"b","weight":1.5},` +
+		`{"from":"a","to":"c","weight":2},` +
+		`{"from":"c","to":"a","weight":3}]}`
+	if string(got) != want {
+		t.Errorf("JSONOrdered() = %s, want %s", got, want)
+	}
+
+	for i := 0; i < 10; i++ {
+		again, _ := g.JSONOrdered()
+		if !bytes.Equal(got, again) {
+			t.Fatalf("JSONOrdered() not stable: %s vs %s", got, again)
+		}
+	}
+}
+
+// 回归测试：有多个上游的 node 需要输出每个上游各自的权重，而不是把后续的上游写到 node 自身的 key 下
+func TestJSONMultipleSources(t *testing.T) {
+	g := buildGraph(
+		[]string{"a", "b", "c", "d"},
+		[][2]string{{"a", "d"}, {"b", "d"}, {"c", "d"}, {"a", "b"}},
+		1, 2, 3, 4,
+	)
+
+	data, err := g.JSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got map[string]map[string]float64
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]map[string]float64{
+		"d": {"a": 1, "b": 2, "c": 3},
+		"b": {"a": 4},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("JSON() = %s, want %v", data, want)
+	}
+}
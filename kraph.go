@@ -1,9 +1,13 @@
 package kraph
 
 import (
+	"context"
 	"fmt"
 	"github.com/pquerna/ffjson/ffjson"
+	"io"
+	"math/rand"
 	"sync"
+	"sync/atomic"
 )
 
 type ID interface {
@@ -39,68 +43,613 @@ func NewNode(id ID) Node {
 	}
 }
 
+// edge definition，表示从 From 指向 To 的一条带权重的边
+type Edge struct {
+	From   ID
+	To     ID
+	Weight float64
+}
+
 // Graph definition
 type Graph interface {
 	// 重置 graph ，会删除其中所有的边和节点
 	Init()
 
+	// 在同一个写锁内将图的全部 node 和边替换为 other 内容的深拷贝，读操作不会看到替换了一半的状态
+	// other 的内容在加锁之前复制，权重不会重新校验，node 数量超过 WithMaxNodes 的上限时返回 ErrCapacityExceeded 且不做任何修改
+	ReplaceContents(other Graph) error
+
+	// 冻结 graph，此后所有修改 node 或边的方法都不会生效：返回 error 的方法返回 ErrFrozen，
+	// 返回 bool 或数量的方法返回 false 或 0，设置了 WithFrozenPanic 时改为 panic，读操作不受影响，冻结无法撤销
+	Freeze()
+
+	// 判断 graph 是否已经被 Freeze 冻结
+	IsFrozen() bool
+
 	// 返回 graph 中所有节点的数量
 	GetNodeCount() int
 
 	// 通过 id 在图中查找节点，如果节点不存在，则会返回 nil
 	GetNode(id ID) Node
 
+	// 通过 id 在图中查找节点，第二个返回值表示节点是否存在
+	LookupNode(id ID) (Node, bool)
+
 	// 返回 graph 中所有node
 	GetNodes() map[ID]Node
 
+	// 在读锁内依次对每个 node 调用 fn，fn 返回 false 时停止遍历，不会复制内部的 map
+	// fn 中不能调用任何修改 graph 的方法，否则会死锁
+	RangeNodes(fn func(id ID, n Node) bool)
+
+	// 在读锁内复制所有 node 后释放锁，再由 workers 个 goroutine 并发地对每个 node 调用 fn，所有调用结束后返回
+	// fn 运行时不持有锁，可以调用 graph 的任何方法，workers 小于 1 时按 1 处理
+	ParallelForEachNode(workers int, fn func(Node))
+
 	// 向图中添加 node 如果该 node 已经存在则返回 false
+	// 如果设置了 WithMaxNodes 且 node 数量已达上限，同样返回 false，可以使用 AddNodes 区分这两种情况
 	AddNode(nd Node) bool
 
+	// 在同一个写锁内批量添加 node，已经存在的 node 会被跳过，返回实际添加的数量
+	// 如果 node 数量达到 WithMaxNodes 设置的上限，则停止添加并返回 ErrCapacityExceeded
+	AddNodes(nds []Node) (int, error)
+
+	// 用 nd 替换图中相同 id 的 node，所有的边保持不变，如果 node 不存在则返回 error
+	ReplaceNode(nd Node) error
+
 	// 从图中删除 node 如果 node 不存在，则返回 false
 	DeleteNode(id ID) bool
 
+	// 从图中删除 node，并返回因此被删除的所有边（包括上游和下游），如果 node 不存在，则返回 false
+	DeleteNodeWithReport(id ID) ([]Edge, bool)
+
+	// 在同一个写锁内批量删除 node 及其所有的边，不存在的 node 会被跳过，返回实际删除的数量
+	DeleteNodes(ids []ID) int
+
 	// 将图中的两个 node 建立关系，并增加权重，如果 node 不存在则返回 error
-	// 如果两个 node 已经存在关系，则权重相加
+	// 如果两个 node 已经存在关系，则权重相加，可以通过 WithEdgeAggregator 修改合并方式
+	// 如果设置了 WithWeightValidator，权重或合并后的权重校验失败时返回校验函数的 error
+	// 除非设置了 WithNonFiniteWeights，权重或相加后的权重为 NaN 或无穷大时返回 ErrNonFiniteWeight
 	AddEdge(id, pid ID, wgt float64) error
 
-	// 替换两个 node 之间的权重，如果 node 不存在则返回 error
+	// 替换两个 node 之间的权重，如果 node 不存在或权重校验失败则返回 error
+	// 除非设置了 WithNonFiniteWeights，权重为 NaN 或无穷大时返回 ErrNonFiniteWeight
 	ReplaceEdge(id, pid ID, wgt float64) error
 
+	// 在同一个写锁内将 edges 中每条边的权重设置为 Weight（覆盖而不是累加），边不存在时创建
+	// 所有的边会先按照 ReplaceEdge 的规则检查，遇到第一条引用了不存在的 node 或权重校验失败的边时返回 error 且不做任何修改
+	ReplaceEdges(edges []Edge) error
+
 	// 删除两个 node 之间的关系，如果 node 不存在则返回 error
 	DeleteEdge(id, pid ID) error
 
-	// 获取两个 node 之间的权重
+	// 获取 pid -> id 这条边的权重，注意参数顺序与 AddEdge 相同：id 为终点，pid 为起点
+	// 新代码建议使用参数顺序更直观的 EdgeWeight(from, to)，node 或边不存在时返回 error
 	GetWeight(id, pid ID) (float64, error)
 
+	// 获取 from -> to 这条边的权重，node 或边不存在时返回 error
+	EdgeWeight(from, to ID) (float64, error)
+
+	// 判断是否存在 from -> to 的边且权重不小于 minWeight，node 或边不存在时返回 false
+	HasEdgeAtLeast(from, to ID, minWeight float64) bool
+
+	// 沿 from 的下游查找 from -> to 的边，返回其权重，node 或边不存在时返回 false
+	TargetWeight(from, to ID) (float64, bool)
+
+	// 沿 to 的上游查找 from -> to 的边，返回其权重，node 或边不存在时返回 false
+	// 结果与 TargetWeight(from, to) 相同
+	SourceWeight(to, from ID) (float64, bool)
+
+	// 返回图中所有的边，按权重排序，descending 为 true 时从大到小，权重相同时按 (From, To) 排序
+	EdgesSortedByWeight(descending bool) []Edge
+
+	// 在读锁内复制所有的边后释放锁，再按 (From, To) 的顺序依次发送到返回的 channel 中
+	// 所有边发送完毕或 ctx 被取消时关闭 channel
+	StreamEdges(ctx context.Context) <-chan Edge
+
+	// 返回图中所有 from == to 的边，按 id 排序
+	SelfLoops() []Edge
+
+	// 按边被添加的顺序返回所有的边，只有使用 WithInsertionOrder 创建的图才会记录顺序，否则返回 nil
+	EdgesInOrder() []Edge
+
 	// 获取给定 node 的所有上游
 	GetSources(id ID) (map[ID]Node, error)
 
 	// 获取给定 node 的所有下游
 	GetTargets(id ID) (map[ID]Node, error)
 
-	// 将整个图输出为 json 格式
+	// 在读锁内依次对 id 的每条下游边调用 fn，fn 返回 false 时停止遍历，如果 node 不存在则返回 error
+	// fn 中不能调用任何修改 graph 的方法，否则会死锁
+	RangeTargets(id ID, fn func(to ID, weight float64) bool) error
+
+	// 在读锁内依次对 id 的每条上游边调用 fn，fn 返回 false 时停止遍历，如果 node 不存在则返回 error
+	// fn 中不能调用任何修改 graph 的方法，否则会死锁
+	RangeSources(id ID, fn func(from ID, weight float64) bool) error
+
+	// 返回 id 的所有下游边，按下游的 id 排序，结果在每次调用之间保持一致，如果 node 不存在则返回 error
+	SortedTargets(id ID) ([]Edge, error)
+
+	// 返回指向 id 的所有上游边，按上游的 id 排序，如果 node 不存在则返回 error
+	SortedSources(id ID) ([]Edge, error)
+
+	// 返回与 id 相连的所有边，包括上游边和下游边，每条边的方向由 From、To 表示，自环只出现一次
+	// 结果按 (From, To) 排序，如果 node 不存在则返回 error
+	IncidentEdges(id ID) ([]Edge, error)
+
+	// 按照下游边的权重成比例地随机选择 id 的一个下游，权重不大于 0 的边不会被选中
+	// 没有可选的下游时返回 error，rng 为 nil 时使用 math/rand 的全局随机源
+	SampleTarget(id ID, rng *rand.Rand) (ID, error)
+
+	// 返回流入 id 的权重最大的上游及其权重，权重相同时返回 id 字符串最小的那个
+	// 如果 node 不存在或没有上游则返回 error
+	HeaviestSource(id ID) (ID, float64, error)
+
+	// 返回 id 流出的权重最大的下游及其权重，权重相同时返回 id 字符串最小的那个
+	// 如果 node 不存在或没有下游则返回 error
+	HeaviestTarget(id ID) (ID, float64, error)
+
+	// 返回 node 的入度，即上游的数量，计数在每次修改边时增量维护，如果 node 不存在则返回 error
+	InDegree(id ID) (int, error)
+
+	// 返回 node 的出度，即下游的数量，计数在每次修改边时增量维护，如果 node 不存在则返回 error
+	OutDegree(id ID) (int, error)
+
+	// 返回所有入度为 0 的 node，按 id 排序，直接读取维护好的入度计数
+	SourceNodes() []ID
+
+	// 以无向图的视角（忽略方向，自环不计入）计算边两端 node 度数的 Pearson 相关系数
+	// 结果为正表示度数大的 node 倾向于相互连接，没有边或所有边两端的度数都相同（例如只有一条边）时结果无定义，返回 NaN
+	DegreeAssortativity() float64
+
+	// 返回所有 node 度数（入度与出度之和，自环计算两次）的平均值，即 2 * 边数 / node 数，空图返回 0
+	AverageDegree() float64
+
+	// 返回度数到具有该度数的 node 数量的映射，直接复制增量维护的计数，不会遍历所有 node
+	DegreeDistribution() map[int]int
+
+	// 获取给定 node 的所有邻居，即上游和下游的并集
+	Neighbors(id ID) (map[ID]Node, error)
+
+	// 获取给定 node 的所有邻居及其权重，如果两个方向都存在边，则权重相加
+	NeighborsWithWeights(id ID) (map[ID]float64, error)
+
+	// 返回同时是 a 和 b 下游的 node，按 id 排序
+	// undirected 为 true 时改为以无向图的视角比较两者的邻居（上游和下游的并集），如果 node 不存在则返回 error
+	CommonNeighbors(a, b ID, undirected bool) ([]ID, error)
+
+	// 以无向图的视角计算 a 和 b 邻居集合的 Jaccard 相似度 |N(a)∩N(b)| / |N(a)∪N(b)|
+	// 两者都没有邻居时返回 0，如果 node 不存在则返回 error
+	JaccardSimilarity(a, b ID) (float64, error)
+
+	// 将 a 和 b 的下游边看作以下游 id 为下标的稀疏权重向量，计算两者的余弦相似度
+	// 任意一方没有下游（或权重全为 0）时返回 0，如果 node 不存在则返回 error
+	CosineSimilarity(a, b ID) (float64, error)
+
+	// 迭代 iterations 轮 SimRank：两个 node 的相似度为 c 乘以它们上游两两之间相似度的平均值，node 与自身的相似度为 1
+	// 只返回相似度大于 threshold 的 node 对，每对只出现一次且较小的 id 在前，不包括 node 与自身
+	// 计算过程需要 O(n²) 的内存，c 不在 (0, 1) 之间或 iterations 小于 0 时返回 nil
+	SimRank(c float64, iterations int, threshold float64) map[[2]ID]float64
+
+	// 以无向图的视角，贪心地优先选择度数小的 node，返回一个极大独立集，结果按 id 排序
+	// 带自环的 node 不会出现在结果中
+	GreedyIndependentSet() []ID
+
+	// 以无向图的视角计算退化序：反复删除当前度数最小的 node（度数相同时取 id 最小的），按删除顺序返回
+	// 第二个返回值为图的退化度，即删除时度数的最大值，自环不计入度数
+	DegeneracyOrdering() ([]ID, int)
+
+	// 使用 2-近似算法返回一个顶点覆盖，即每条边至少有一个端点在结果中，结果按 id 排序
+	VertexCover() []ID
+
+	// 返回图的补图：对于每一对不同的 node，如果原图中不存在 from -> to 的边，则在新图中以 wgt 为权重建立这条边
+	// 新图不包含自环，node 与原图共享
+	// wgt 按本图的 WithWeightValidator 和 WithNonFiniteWeights 校验，不通过时返回 error
+	Complement(wgt float64) (Graph, error)
+
+	// 返回两个图的并集，包含两个图中所有的 node 和边，两个图中都存在的边权重相加，不会修改原图
+	// 来自 other 的权重（包括相加后的权重）按本图的 WithWeightValidator 和 WithNonFiniteWeights 校验，不通过时返回包含该边的 error
+	// 因此与 Complement、CollapseMultiEdges 一样，Union 除了新图还会返回 error，而不是只返回 Graph
+	// 新图沿用本图的 WithMaxNodes 上限，合并后的 node 数量超过上限时返回 ErrCapacityExceeded
+	Union(other Graph) (Graph, error)
+
+	// 返回两个图的交集，只包含两个图中都存在的 node 和边，边的权重取两者中较小的值，不会修改原图
+	Intersection(other Graph) Graph
+
+	// 返回两个图的差集，包含原图所有的 node，以及原图中存在但 other 中不存在的边，权重保持不变，不会修改原图
+	Difference(other Graph) Graph
+
+	// 返回只包含 keep 返回 true 的 node 及其之间的边的导出子图，权重保持不变，不会修改原图
+	// keep 在读锁内调用，不能在其中修改 graph
+	FilterNodes(keep func(Node) bool) Graph
+
+	// 返回包含原图所有 node，但只包含 keep 返回 true 的边的新图，不会修改原图
+	// keep 在读锁内调用，不能在其中修改 graph
+	FilterEdges(keep func(from, to ID, w float64) bool) Graph
+
+	// 返回从 roots 出发沿下游方向可以到达的所有 node（包括 roots 本身）及它们之间的边组成的新图，不会修改原图
+	// 如果某个 root 不存在则返回 error
+	ReachableSubgraph(roots []ID) (Graph, error)
+
+	// 忽略边的方向，按 id 的顺序对每个连通分量做 BFS，返回包含所有 node 的生成森林以及每棵树的根
+	// 根是各分量中 id 最小的 node，森林中的边保留原图中的方向和权重，两个方向的边都存在时保留从父节点指向子节点的那条
+	SpanningForest() (Graph, []ID)
+
+	// 返回每对 node 之间只有一条边的新图，边的权重为 combine(该方向上所有平行边的权重)，不会修改原图
+	// 目前的 graph 不存储平行边，每对 node 在同一方向上最多只有一条边，combine 收到的切片长度总是 1，
+	// 因此在支持平行边之前，这个方法实际上只是对每条边单独调用 combine 重新计算权重，再返回一个副本
+	// combine 的结果按本图的 WithWeightValidator 和 WithNonFiniteWeights 校验，不通过时返回包含该边的 error
+	// combine 在读锁内调用，不能在其中修改 graph
+	CollapseMultiEdges(combine func(weights []float64) float64) (Graph, error)
+
+	// 使用 Dijkstra 算法计算 from 到 to 的最短路径，avoid 中的 node 视为不存在
+	// 返回路径上的 node 和总权重，如果无法到达则返回 ErrUnreachable，边的权重不能为负
+	ShortestPathAvoiding(from, to ID, avoid map[ID]bool) ([]ID, float64, error)
+
+	// 从 source 运行一次 Dijkstra，返回每个可达 node 的最短距离以及最短路径树中的前驱
+	// 无法到达的 node 不在 dist 中，source 没有前驱，边的权重不能为负，如果 source 不存在则返回 error
+	ShortestPathTree(source ID) (dist map[ID]float64, prev map[ID]ID, err error)
+
+	// 计算 from 到 to 的最宽路径，即路径上最小的边权重（瓶颈）最大的路径，返回路径和瓶颈容量
+	// from 与 to 相同时返回只包含 from 的路径，容量为 +Inf，如果无法到达则返回 ErrUnreachable
+	WidestPath(from, to ID) ([]ID, float64, error)
+
+	// 返回从 from 出发、最短路径总权重不超过 budget 的所有 node 及其最短距离，包括距离为 0 的 from
+	// 超出 budget 的路径不会继续扩展，遇到负权重的边或 budget 为负数时返回 error
+	ReachableWithinBudget(from ID, budget float64) (map[ID]float64, error)
+
+	// 使用 A* 算法计算 from 到 to 的最短路径，边的权重为步长，heuristic(id) 为 id 到 to 的估计距离，不能大于真实距离
+	// heuristic 为 nil 或总是返回 0 时与 Dijkstra 相同，返回路径上的 node 和总权重
+	// 无法到达时返回 ErrUnreachable，边的权重不能为负，heuristic 在读锁内调用，不能在其中修改 graph
+	AStar(from, to ID, heuristic func(ID) float64) ([]ID, float64, error)
+
+	// 使用 Yen 算法计算 from 到 to 的前 k 条无环最短路径，按总权重从小到大排列
+	// 如果路径不足 k 条，则返回所有找到的路径，如果一条路径都没有则返回 ErrUnreachable
+	KShortestPaths(from, to ID, k int) ([]Path, error)
+
+	// 使用双向 Dijkstra 计算 from 到 to 的最短路径，分别从 from 沿下游、从 to 沿上游搜索并在中间相遇
+	// 结果与 ShortestPathAvoiding 相同，但通常访问的 node 更少
+	ShortestPathBidirectional(from, to ID) ([]ID, float64, error)
+
+	// 在有向无环图中使用拓扑序松弛计算 from 到 to 总权重最大的路径
+	// 图中存在环时返回 ErrCycle，无法到达时返回 ErrUnreachable
+	MaxWeightPath(from, to ID) ([]ID, float64, error)
+
+	// 使用带记忆化的 DFS 统计有向无环图中 from 到 to 不同路径的数量，from 与 to 相同时为 1
+	// 从 from 可达的部分存在环时返回 ErrCycle，数量超出 int64 范围时返回 error
+	CountPaths(from, to ID) (int64, error)
+
+	// 判断图是否为有向无环图，使用 DFS 并在遇到第一条回边时立即返回，自环也视为环
+	IsDAG() bool
+
+	// 判断添加 from -> to 这条边是否会形成新的环，即 to 是否已经能够到达 from，不会修改图
+	// from 与 to 相同时（自环）返回 true，如果 node 不存在则返回 error
+	WouldCreateCycle(from, to ID) (bool, error)
+
+	// 在同一个写锁内检查并添加 from -> to 的边，会形成环时返回 ErrCycle 且不做任何修改，其它规则与 AddEdge 相同
+	AddEdgeIfAcyclic(from, to ID, wgt float64) error
+
+	// 使用 Johnson 算法计算所有 node 之间的最短距离，允许负权重：先用 Bellman-Ford 重新赋权，再从每个 node 运行 Dijkstra
+	// 返回 from -> to -> 距离，不可达的组合不在结果中，存在负权环时返回 ErrNegativeCycle
+	AllPairsJohnson() (map[ID]map[ID]float64, error)
+
+	// 使用 Hierholzer 算法返回一条恰好经过每条边一次的欧拉路径（起点等于终点时为欧拉回路）
+	// 不满足有向图欧拉路径的度数条件或边不连通时返回 error，图中没有边时返回空路径
+	EulerianPath() ([]ID, error)
+
+	// 以 sources 中所有 node 为起点同时进行 BFS，返回每个 node 到最近起点的跳数（沿下游方向）
+	// 起点的距离为 0，无法到达的 node 不在结果中，如果某个起点不存在则返回 error
+	MultiSourceDistances(sources []ID) (map[ID]int, error)
+
+	// 从 start 出发，按照 Prim 算法的顺序沿下游方向扩展：每次从离开已发现集合的边中取出权重最小的一条调用 visit，
+	// 然后将其终点加入已发现集合，终点已经被发现的边会被跳过，权重相同时按 (from, to) 的字符串顺序
+	// visit 返回 false 时停止遍历，visit 在读锁内调用，不能在其中修改 graph，如果 start 不存在则返回 error
+	WalkByWeight(start ID, visit func(from, to ID, w float64) bool) error
+
+	// 使用 Johnson 算法列出有向图中所有的简单环，每个环从其中 id 最小的 node 开始
+	// 自环作为只包含一个 node 的环返回，环的数量可能非常多，只适合中等规模的图
+	AllCycles() [][]ID
+
+	// 将每个强连通分量收缩为一个 node，返回得到的有向无环图，以及原图 node id 到其所在分量 node id 的映射
+	// 分量 node 的 id 为分量中最小的 node id，分量之间的边权重为原图中对应边的权重之和
+	Condensation() (Graph, map[ID]ID, error)
+
+	// 返回每个 node 沿下游方向可以到达的其他 node 的数量（不包括自身）
+	// 基于强连通分量收缩计算，同一个强连通分量中的 node 数量相同
+	DescendantCounts() map[ID]int
+
+	// 判断 a 和 b 是否属于同一个强连通分量，即两者是否相互可达
+	// 分量编号在第一次调用时计算并缓存，直到图中的 node 或边发生变化，如果 node 不存在则返回 error
+	InSameSCC(a, b ID) (bool, error)
+
+	// 使用并查集计算弱连通分量（忽略边的方向）的大小，按从大到小排列，不会构建每个分量的成员列表
+	ComponentSizes() []int
+
+	// 判断 a 和 b 在忽略边的方向时是否连通，任意一个 node 不存在时返回 false
+	// 使用 WithIncrementalConnectivity 创建的图查询接近 O(1)，否则每次调用都会做一次 BFS
+	Connected(a, b ID) bool
+
+	// 将每个 node 所有下游边的权重除以其权重之和，使其和为 1，没有下游、权重和为 0 或非有限值，或者结果无法通过权重校验的 node 保持不变
+	NormalizeOutWeights()
+
+	// 将每个 node 所有上游边的权重除以其权重之和，使其和为 1，没有上游、权重和为 0 或非有限值，或者结果无法通过权重校验的 node 保持不变
+	NormalizeInWeights()
+
+	// 在写锁内将每条边的权重替换为 f(权重)，f 的结果需要通过与 ReplaceEdge 相同的校验（包括 NaN 和无穷大）
+	// 任何一条边校验失败时返回 error 且不修改任何边
+	ScaleWeights(f func(float64) float64) error
+
+	// 将所有边的权重按 [min, max] 等宽分为 bins 个区间，返回每个区间的边数以及最小和最大权重
+	// 最大权重计入最后一个区间，所有权重相同时全部计入第一个区间，图中没有边或 bins 小于 1 时返回 nil
+	// NaN 和无穷大的权重（WithNonFiniteWeights）不参与统计，也不影响最小和最大权重，没有有限权重时同样返回 nil
+	WeightHistogram(bins int) ([]int, float64, float64)
+
+	// 返回 id 的离心率，即沿下游方向从 id 到其它所有 node 的最大跳数
+	// 如果有 node 无法从 id 到达，离心率为无穷大，此时返回 ErrUnreachable
+	Eccentricity(id ID) (int, error)
+
+	// 返回图的直径，即所有 node 离心率的最大值
+	// 如果图不是强连通的则返回 ErrUnreachable，空图返回 error
+	Diameter() (int, error)
+
+	// 返回图的半径，即所有 node 离心率的最小值，与 Diameter 一样，图不是强连通时返回 ErrUnreachable
+	Radius() (int, error)
+
+	// 返回离心率等于半径的所有 node，按 id 排序，图为空或不是强连通时返回 nil
+	Center() []ID
+
+	// 以无向图的视角使用标签传播算法划分社区，每轮中 node 按随机顺序采用邻居中出现次数最多的标签
+	// 平局时随机选择，直到标签不再变化或达到 maxIter 轮，返回每个 node 的社区编号（从 0 开始）
+	// rng 用于保证结果可复现，为 nil 时使用固定的种子
+	LabelPropagation(maxIter int, rng *rand.Rand) map[ID]int
+
+	// 以无向加权图的视角使用 Louvain 算法划分社区（两个方向的边权重相加），
+	// 返回每个 node 的社区编号（从 0 开始）以及最终划分的模块度
+	Louvain() (map[ID]int, float64)
+
+	// 以无向加权图的视角（两个方向的边权重相加）计算划分 partition 的 Newman 模块度
+	// partition 中没有的 node 各自单独作为一个社区，图中不存在的 node 会被忽略
+	Modularity(partition map[ID]int) float64
+
+	// 将 from -> to 的边反转为 to -> from，权重不变，如果 to -> from 已经存在，则按 AddEdge 的规则合并权重
+	// 合并后的权重同样需要通过校验，否则返回 error 且不做任何修改，TypedGraph 边上的值跟随边一起反转
+	// node 或边不存在时返回 error
+	ReverseEdge(from, to ID) error
+
+	// 在写锁内交换上游和下游的索引，将图中所有的边反转方向，权重不变，不会复制任何 node 或边
+	// 自环保持不变，TypedGraph 边上的值和 WithInsertionOrder 记录的顺序跟随边一起反转
+	TransposeInPlace()
+
+	// 将 id 拆分为两个 node：创建 newID，把 assignTarget(to) 返回 true 的下游边从 id 移到 newID 上，权重不变，
+	// 并添加一条权重为 1 的 id -> newID 的边保持连通，上游边全部留在 id 上，自环 id -> id 被移动时变为 newID -> id
+	// id 不存在、newID 已经存在或达到 WithMaxNodes 的上限时返回 error 且不做任何修改
+	SplitNode(id, newID ID, assignTarget func(to ID) bool) error
+
+	// 删除 node，并对每一对 pred -> id -> succ 建立 pred -> succ 的边，权重为两条边的权重之和
+	// 如果 pred -> succ 已经存在则按 AddEdge 的规则合并权重，由此产生的自环会被丢弃，如果 node 不存在则返回 error
+	RemoveAndBypass(id ID) error
+
+	// 与 RemoveAndBypass 相同，但 pred -> succ 的权重由 combine(pred -> id 的权重, id -> succ 的权重) 计算
+	RemoveAndBypassWith(id ID, combine func(in, out float64) float64) error
+
+	// 使用幂迭代计算特征向量中心性，node 的得分为其所有上游得分的加权和，每轮迭代后做 L2 归一化
+	// 在 iterations 轮内各 node 变化之和小于 node 数量乘以 tolerance 时视为收敛，否则返回 error
+	// 对于无向图，请将每条边以两个方向分别加入
+	EigenvectorCentrality(iterations int, tolerance float64) (map[ID]float64, error)
+
+	// 使用 x = alpha * Aᵀx + beta 的递推计算 Katz 中心性，最多迭代 iterations 轮，结果不做归一化
+	// 如果 alpha 不小于邻接矩阵谱半径（通过幂迭代估计）的倒数，或者在 iterations 轮内未收敛则返回 error
+	KatzCentrality(alpha, beta float64, iterations int) (map[ID]float64, error)
+
+	// 计算 PageRank，每个 node 的得分平均分给其所有下游，没有下游的 node 将得分平均分给所有 node
+	// 共迭代 iterations 轮，结果之和为 1
+	PageRank(damping float64, iterations int) map[ID]float64
+
+	// 与 PageRank 相同，但每轮迭代中将 node 划分给 workers 个 goroutine 并行计算，结果与 PageRank 一致
+	// 只在构建内部索引时持有读锁
+	PageRankParallel(damping float64, iterations int, workers int) map[ID]float64
+
+	// 与 PageRank 相同，但每个 node 按下游边的权重占其出强度（所有正权重之和）的比例分配得分
+	// 权重不大于 0 的边不分配得分，出强度为 0 的 node 与没有下游的 node 一样将得分分给所有 node
+	WeightedPageRank(damping float64, iterations int) map[ID]float64
+
+	// 计算个性化 PageRank，随机跳转以及没有下游的 node 的得分都按 seeds 归一化后的分布分配
+	// seeds 中不存在的 node 和非正数的权重会被忽略，seeds 为空时退化为普通的 PageRank
+	PersonalizedPageRank(seeds map[ID]float64, damping float64, iterations int) map[ID]float64
+
+	// 返回图的运行指标，其中的计数在每次修改时增量维护，不需要遍历整个图
+	Stats() GraphStats
+
+	// 将整个图输出为 json 格式：{"下游 id": {"上游 id": 权重, ...}, ...}，没有上游的 node 不出现在结果中
 	JSON() ([]byte, error)
+
+	// 与 JSON 的输出相同，但只在复制图的内容时持有读锁，编码在锁外进行，不会长时间阻塞写操作
+	JSONSnapshot() ([]byte, error)
+
+	// 将整个图以 {"nodes": [...], "edges": [...]} 的 json 格式流式写入 w，不会在内存中构建完整的结果
+	// 存在权重为 NaN 或无穷大的边时返回 ErrNonFiniteWeight，且不会向 w 写入任何内容
+	WriteJSON(w io.Writer) error
+
+	// 以与 WriteJSON 相同的 {"nodes": [...], "edges": [...]} 格式输出整个图
+	// node 按 id 排序，边按 (from, to) 排序，相同的图每次输出的字节完全相同
+	JSONOrdered() ([]byte, error)
+
+	// 以 NetworkX node_link_data 的 JSON 格式导出整个图，directed 为 true，边的权重保存在 weight 字段中
+	NetworkXJSON() ([]byte, error)
+
+	// 以邻接表的形式将整个图写入 w，每行是一个 node 及其下游和权重，例如 a -> b(1.0), c(2.5)
+	// node 和下游都按 id 排序，没有下游的 node 只输出 id
+	WriteAdjacencyList(w io.Writer) error
+
+	// 返回邻接表形式的字符串，格式与 WriteAdjacencyList 相同，方便调试
+	String() string
+
+	// 将整个图以 GraphML 格式写入 w，边为有向边，权重保存在 key 为 weight 的 data 中
+	GraphML(w io.Writer) error
+
+	// 将整个图以 Gephi 使用的 GEXF 格式写入 w，边为有向边，权重保存在 weight 属性中
+	GEXF(w io.Writer) error
 }
 
-func NewGraph() Graph {
-	return &graph{
+func NewGraph(opts ...Option) Graph {
+	cfg := config{}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	return newGraph(cfg)
+}
+
+// BuildFromEdgeList 创建一个新图，并在同一个写锁内按照 AddEdge 的规则依次添加 edges 中的边
+// autoCreateNodes 为 true 时自动创建边引用的 node，否则遇到不存在的 node 时返回包含该边的 error
+func BuildFromEdgeList(edges []Edge, autoCreateNodes bool, opts ...Option) (Graph, error) {
+	g := NewGraph(opts...).(*graph)
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	for _, e := range edges {
+		from, to := g.cfg.key(e.From), g.cfg.key(e.To)
+		if autoCreateNodes {
+			for _, id := range []ID{from, to} {
+				if g.unsafeIdExist(id) {
+					continue
+				}
+
+				if g.unsafeFull() {
+					return nil, fmt.Errorf("edge %s -> %s: %w", e.From, e.To, ErrCapacityExceeded)
+				}
+				g.unsafeAddNode(NewNode(id))
+			}
+		}
+
+		if err := g.unsafeAddEdgeChecked(from, to, e.Weight); err != nil {
+			return nil, fmt.Errorf("edge %s -> %s: %w", e.From, e.To, err)
+		}
+	}
+
+	return g, nil
+}
+
+func newGraph(cfg config) *graph {
+	g := &graph{
+		cfg:         cfg,
+		st:          newStats(),
 		nodeList:    make(map[ID]Node),
 		nodeSources: make(map[ID]map[ID]float64),
 		nodeTargets: make(map[ID]map[ID]float64),
+		degrees:     make(map[ID]*nodeDegree),
+	}
+	if cfg.insertionOrder {
+		g.order = newEdgeOrder()
+	}
+	if cfg.connectivity {
+		g.conn = newConnectivity()
 	}
+
+	return g
 }
 
 type graph struct {
 	mu          sync.RWMutex
+	cfg         config
+	st          stats
 	nodeList    map[ID]Node
 	nodeSources map[ID]map[ID]float64
 	nodeTargets map[ID]map[ID]float64
+	degrees     map[ID]*nodeDegree
+
+	// InSameSCC 使用的强连通分量编号缓存，在读锁内由 sccMu 保护填充，
+	// 在写锁内由 unsafeInvalidate 清空，为 nil 表示需要重新计算
+	sccMu sync.Mutex
+	scc   map[ID]int
+
+	// TypedGraph 附加在边上的值，只有通过 AddEdgeValue 写入过才会创建，在 unsafeRemoveEdge 中随边一起删除
+	edgeVals map[edgeKey]interface{}
+
+	// WithInsertionOrder 时按添加顺序记录的边，否则为 nil
+	order *edgeOrder
+
+	// WithIncrementalConnectivity 时维护的并查集，否则为 nil
+	// 写锁内直接修改，Connected 在读锁内由 connMu 保护重新构建和查询
+	connMu sync.Mutex
+	conn   *connectivity
+
+	// Freeze 之后为 true，所有修改 graph 的方法在写锁内通过 unsafeCheckFrozen 检查
+	frozen bool
+}
+
+// 图的结构（node 或边的存在性）发生变化时清空依赖结构的缓存，调用方需要持有写锁
+func (g *graph) unsafeInvalidate() {
+	g.scc = nil
+}
+
+// node 的入度和出度计数，在 unsafeSetEdge 和 unsafeRemoveEdge 中维护
+type nodeDegree struct {
+	in, out int
 }
 
 func (g *graph) Init() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.unsafeCheckFrozen() != nil {
+		return
+	}
+
 	g.nodeList = make(map[ID]Node)
 	g.nodeSources = make(map[ID]map[ID]float64)
 	g.nodeTargets = make(map[ID]map[ID]float64)
+	g.degrees = make(map[ID]*nodeDegree)
+	g.edgeVals = nil
+	g.st.reset()
+	g.unsafeInvalidate()
+	if g.order != nil {
+		g.order = newEdgeOrder()
+	}
+	if g.conn != nil {
+		g.conn = newConnectivity()
+	}
+}
+
+func (g *graph) ReplaceContents(other Graph) error {
+	// 先拷贝 other，避免同时持有两个图的锁，other 与 g 相同时也不会死锁
+	ng := snapshotGraph(other).unsafeRekey(g.cfg)
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if err := g.unsafeCheckFrozen(); err != nil {
+		return err
+	}
+
+	if g.cfg.maxNodes > 0 && len(ng.nodeList) > g.cfg.maxNodes {
+		return ErrCapacityExceeded
+	}
+
+	g.nodeList = ng.nodeList
+	g.nodeSources = ng.nodeSources
+	g.nodeTargets = ng.nodeTargets
+	g.degrees = ng.degrees
+	g.edgeVals = nil
+	g.unsafeInvalidate()
+	if g.order != nil {
+		// other 的添加顺序无法得知，按 (From, To) 的顺序重新记录
+		g.order = newEdgeOrder()
+		for _, e := range g.unsafeEdges() {
+			g.order.add(e.From, e.To)
+		}
+	}
+	if g.conn != nil {
+		g.conn.stale = true
+	}
+
+	// 调用次数的累计值保持不变
+	g.st.edges = ng.st.edges
+	g.st.degreeCount = ng.st.degreeCount
+	g.st.maxDegree = ng.st.maxDegree
+
+	return nil
 }
 
 func (g *graph) GetNodeCount() int {
@@ -111,12 +660,25 @@ func (g *graph) GetNodeCount() int {
 }
 
 func (g *graph) GetNode(id ID) Node {
+	id = g.cfg.key(id)
+
 	g.mu.RLock()
 	defer g.mu.RUnlock()
 
 	return g.nodeList[id]
 }
 
+func (g *graph) LookupNode(id ID) (Node, bool) {
+	id = g.cfg.key(id)
+
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	nd, ok := g.nodeList[id]
+
+	return nd, ok
+}
+
 func (g *graph) GetNodes() map[ID]Node {
 	g.mu.RLock()
 	defer g.mu.RUnlock()
@@ -124,6 +686,52 @@ func (g *graph) GetNodes() map[ID]Node {
 	return g.nodeList
 }
 
+func (g *graph) RangeNodes(fn func(id ID, n Node) bool) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	for id, nd := range g.nodeList {
+		if !fn(id, nd) {
+			return
+		}
+	}
+}
+
+func (g *graph) ParallelForEachNode(workers int, fn func(Node)) {
+	g.mu.RLock()
+	nodes := make([]Node, 0, len(g.nodeList))
+	for _, nd := range g.nodeList {
+		nodes = append(nodes, nd)
+	}
+	g.mu.RUnlock()
+
+	if workers < 1 {
+		workers = 1
+	}
+	if workers > len(nodes) {
+		workers = len(nodes)
+	}
+
+	// 每个 worker 依次领取下一个 node，fn 的耗时不均匀时也能保持负载均衡
+	var next int64 = -1
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for {
+				i := atomic.AddInt64(&next, 1)
+				if i >= int64(len(nodes)) {
+					return
+				}
+				fn(nodes[i])
+			}
+		}()
+	}
+	wg.Wait()
+}
+
 func (g *graph) unsafeIdExist(id ID) bool {
 	_, ok := g.nodeList[id]
 
@@ -134,86 +742,368 @@ func (g *graph) AddNode(nd Node) bool {
 	g.mu.Lock()
 	defer g.mu.Unlock()
 
+	if g.unsafeCheckFrozen() != nil {
+		return false
+	}
+
 	// 如果这个节点已经存在，返回false
-	if g.unsafeIdExist(nd.GetId()) {
+	if g.unsafeIdExist(g.cfg.key(nd.GetId())) {
 		return false
 	}
 
-	id := nd.GetId()
-	g.nodeList[id] = nd
+	if g.unsafeFull() {
+		return false
+	}
+
+	g.unsafeAddNode(nd)
 
 	return true
 }
 
+func (g *graph) AddNodes(nds []Node) (int, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if err := g.unsafeCheckFrozen(); err != nil {
+		return 0, err
+	}
+
+	added := 0
+	for _, nd := range nds {
+		if g.unsafeIdExist(g.cfg.key(nd.GetId())) {
+			continue
+		}
+
+		if g.unsafeFull() {
+			return added, ErrCapacityExceeded
+		}
+
+		g.unsafeAddNode(nd)
+		added++
+	}
+
+	return added, nil
+}
+
+// 判断 node 数量是否已经达到 WithMaxNodes 设置的上限
+func (g *graph) unsafeFull() bool {
+	return g.cfg.maxNodes > 0 && len(g.nodeList) >= g.cfg.maxNodes
+}
+
+// 直接向图中写入 node，调用方需要持有写锁并保证 node 不存在
+func (g *graph) unsafeAddNode(nd Node) {
+	id := g.cfg.key(nd.GetId())
+	g.nodeList[id] = nd
+	g.degrees[id] = &nodeDegree{}
+	g.st.moveDegree(-1, 0)
+	g.unsafeInvalidate()
+	if g.conn != nil {
+		g.conn.addNode(id)
+	}
+}
+
+func (g *graph) ReplaceNode(nd Node) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if err := g.unsafeCheckFrozen(); err != nil {
+		return err
+	}
+
+	id := g.cfg.key(nd.GetId())
+	if !g.unsafeIdExist(id) {
+		return fmt.Errorf("%s does not exist in graph", id)
+	}
+	g.nodeList[id] = nd
+
+	return nil
+}
+
 func (g *graph) DeleteNode(id ID) bool {
+	id = g.cfg.key(id)
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.unsafeCheckFrozen() != nil {
+		return false
+	}
+
+	_, ok := g.unsafeDeleteNode(id)
+
+	return ok
+}
+
+func (g *graph) DeleteNodeWithReport(id ID) ([]Edge, bool) {
+	id = g.cfg.key(id)
+
 	g.mu.Lock()
 	defer g.mu.Unlock()
 
+	if g.unsafeCheckFrozen() != nil {
+		return nil, false
+	}
+
+	return g.unsafeDeleteNode(id)
+}
+
+func (g *graph) DeleteNodes(ids []ID) int {
+	ids = g.cfg.keys(ids)
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.unsafeCheckFrozen() != nil {
+		return 0
+	}
+
+	deleted := 0
+	for _, id := range ids {
+		if _, ok := g.unsafeDeleteNode(id); ok {
+			deleted++
+		}
+	}
+
+	return deleted
+}
+
+func (g *graph) unsafeDeleteNode(id ID) ([]Edge, bool) {
+	g.st.deleteCalls++
+
 	// 如果这个 id 在 node list 中不存在 直接返回false
 	if !g.unsafeIdExist(id) {
-		return false
+		return nil, false
 	}
+
+	removed := make([]Edge, 0, len(g.nodeTargets[id])+len(g.nodeSources[id]))
+	for tid, wgt := range g.nodeTargets[id] {
+		removed = append(removed, Edge{From: id, To: tid, Weight: wgt})
+	}
+
+	for sid, wgt := range g.nodeSources[id] {
+		// 自环已经在下游中记录过了
+		if sid != id {
+			removed = append(removed, Edge{From: sid, To: id, Weight: wgt})
+		}
+	}
+
+	for _, e := range removed {
+		g.unsafeRemoveEdge(e.From, e.To)
+	}
+
 	delete(g.nodeList, id)
 	delete(g.nodeTargets, id)
+	delete(g.nodeSources, id)
+	delete(g.degrees, id)
+	g.st.moveDegree(0, -1)
+	g.unsafeInvalidate()
+	if g.conn != nil {
+		g.conn.stale = true
+	}
+
+	sortEdges(removed)
 
-	for _, tmap := range g.nodeTargets {
-		delete(tmap, id)
+	return removed, true
+}
+
+// 返回 node 的度数，即入度与出度之和，自环计算两次，调用方需要保证 node 存在
+func (g *graph) unsafeDegree(id ID) int {
+	d := g.degrees[id]
+
+	return d.in + d.out
+}
+
+func (g *graph) InDegree(id ID) (int, error) {
+	id = g.cfg.key(id)
+
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	d, ok := g.degrees[id]
+	if !ok {
+		return 0, fmt.Errorf("%s does not exist in graph", id)
 	}
 
-	delete(g.nodeSources, id)
+	return d.in, nil
+}
+
+func (g *graph) OutDegree(id ID) (int, error) {
+	id = g.cfg.key(id)
+
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	d, ok := g.degrees[id]
+	if !ok {
+		return 0, fmt.Errorf("%s does not exist in graph", id)
+	}
+
+	return d.out, nil
+}
+
+func (g *graph) SourceNodes() []ID {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	ids := make([]ID, 0)
+	for id, d := range g.degrees {
+		if d.in == 0 {
+			ids = append(ids, id)
+		}
+	}
+	sortIDs(ids)
+
+	return ids
+}
+
+// 直接写入 from -> to 的权重，调用方需要持有写锁并保证两个 node 都存在
+func (g *graph) unsafeSetEdge(from, to ID, wgt float64) {
+	if _, ok := g.nodeTargets[from][to]; ok {
+		g.nodeTargets[from][to] = wgt
+		g.nodeSources[to][from] = wgt
+
+		return
+	}
+
+	df, dt := g.unsafeDegree(from), g.unsafeDegree(to)
+
+	if g.order != nil {
+		g.order.add(from, to)
+	}
+	if g.conn != nil {
+		g.conn.addEdge(from, to)
+	}
+
+	if _, ok := g.nodeTargets[from]; !ok {
+		g.nodeTargets[from] = make(map[ID]float64)
+	}
+	g.nodeTargets[from][to] = wgt
+
+	if _, ok := g.nodeSources[to]; !ok {
+		g.nodeSources[to] = make(map[ID]float64)
+	}
+	g.nodeSources[to][from] = wgt
+
+	g.degrees[from].out++
+	g.degrees[to].in++
+	g.st.edges++
+	g.unsafeInvalidate()
+	g.st.moveDegree(df, g.unsafeDegree(from))
+	if to != from {
+		g.st.moveDegree(dt, g.unsafeDegree(to))
+	}
+}
+
+// 删除 from -> to 的边，如果边不存在则返回 false，调用方需要持有写锁
+func (g *graph) unsafeRemoveEdge(from, to ID) bool {
+	if _, ok := g.nodeTargets[from][to]; !ok {
+		return false
+	}
+
+	df, dt := g.unsafeDegree(from), g.unsafeDegree(to)
 
-	for _, smap := range g.nodeSources {
-		delete(smap, id)
+	delete(g.nodeTargets[from], to)
+	if len(g.nodeTargets[from]) == 0 {
+		delete(g.nodeTargets, from)
+	}
+
+	delete(g.nodeSources[to], from)
+	if len(g.nodeSources[to]) == 0 {
+		delete(g.nodeSources, to)
+	}
+
+	g.degrees[from].out--
+	g.degrees[to].in--
+	g.unsafeInvalidate()
+	if g.edgeVals != nil {
+		delete(g.edgeVals, edgeKey{from, to})
+	}
+	if g.order != nil {
+		g.order.remove(from, to)
+	}
+	if g.conn != nil {
+		g.conn.stale = true
+	}
+
+	g.st.edges--
+	g.st.moveDegree(df, g.unsafeDegree(from))
+	if to != from {
+		g.st.moveDegree(dt, g.unsafeDegree(to))
 	}
 
 	return true
 }
 
 func (g *graph) AddEdge(id, pid ID, wgt float64) error {
+	id, pid = g.cfg.key(id), g.cfg.key(pid)
+
 	// 如果已经存在此条关系，则增加其权重，如果没有则创建
 	g.mu.Lock()
 	defer g.mu.Unlock()
 
-	if !g.unsafeIdExist(id) {
-		return fmt.Errorf("%s does not exist in graph", id)
+	if err := g.unsafeCheckFrozen(); err != nil {
+		return err
 	}
 
-	if !g.unsafeIdExist(pid) {
-		return fmt.Errorf("%s does not exist in graph", pid)
-	}
+	return g.unsafeAddEdgeChecked(pid, id, wgt)
+}
 
-	if _, ok := g.nodeTargets[pid]; ok {
-		if w, ok2 := g.nodeTargets[pid][id]; ok2 {
-			g.nodeTargets[pid][id] = w + wgt
-		} else {
-			g.nodeTargets[pid][id] = wgt
-		}
-	} else {
-		g.nodeTargets[pid] = map[ID]float64{
-			id: wgt,
-		}
+// 按照 AddEdge 的规则检查 node 和权重并合并 from -> to 的权重，调用方需要持有写锁
+func (g *graph) unsafeAddEdgeChecked(from, to ID, wgt float64) error {
+	g.st.addEdgeCalls++
+
+	if !g.unsafeIdExist(to) {
+		return fmt.Errorf("%s does not exist in graph", to)
 	}
 
-	if _, ok := g.nodeSources[id]; ok {
-		if w, ok2 := g.nodeSources[id][pid]; ok2 {
-			g.nodeSources[id][pid] = w + wgt
-		} else {
-			g.nodeSources[id][pid] = wgt
-		}
+	if !g.unsafeIdExist(from) {
+		return fmt.Errorf("%s does not exist in graph", from)
+	}
 
-	} else {
-		g.nodeSources[id] = map[ID]float64{
-			pid: wgt,
-		}
+	// 两个有效的权重合并后也可能溢出为无穷大或者超出 validator 允许的范围
+	merged, err := g.unsafeMergedWeight(from, to, wgt)
+	if err != nil {
+		return err
 	}
 
+	g.unsafeSetEdge(from, to, merged)
+
 	return nil
 }
 
+// 返回将 wgt 合并到 from -> to 之后的权重，边不存在时直接返回 wgt
+func (g *graph) unsafeAggregate(from, to ID, wgt float64) float64 {
+	if w, ok := g.nodeTargets[from][to]; ok {
+		return g.cfg.aggregate(w, wgt)
+	}
+
+	return wgt
+}
+
+// 返回按照 AddEdge 的规则与已有的 from -> to 合并后的权重，合并前后的权重都要通过 validateWeight，调用方需要持有锁
+func (g *graph) unsafeMergedWeight(from, to ID, wgt float64) (float64, error) {
+	if err := g.cfg.validateWeight(wgt); err != nil {
+		return 0, err
+	}
+
+	merged := g.unsafeAggregate(from, to, wgt)
+	if err := g.cfg.validateWeight(merged); err != nil {
+		return 0, err
+	}
+
+	return merged, nil
+}
+
 func (g *graph) ReplaceEdge(id, pid ID, wgt float64) error {
+	id, pid = g.cfg.key(id), g.cfg.key(pid)
+
 	g.mu.Lock()
 	defer g.mu.Unlock()
 
+	if err := g.unsafeCheckFrozen(); err != nil {
+		return err
+	}
+
 	if !g.unsafeIdExist(id) {
 		return fmt.Errorf("%s does not exist in graph", id)
 	}
@@ -222,30 +1112,59 @@ func (g *graph) ReplaceEdge(id, pid ID, wgt float64) error {
 		return fmt.Errorf("%s does not exist in graph", pid)
 	}
 
-	if _, ok := g.nodeTargets[pid]; ok {
-		g.nodeTargets[pid][id] = wgt
-	} else {
-		g.nodeTargets[pid] = map[ID]float64{
-			id: wgt,
-		}
+	if err := g.cfg.validateWeight(wgt); err != nil {
+		return err
 	}
 
-	if _, ok := g.nodeSources[id]; ok {
-		g.nodeSources[id][pid] = wgt
-	} else {
-		g.nodeSources[id] = map[ID]float64{
-			pid: wgt,
+	g.unsafeSetEdge(pid, id, wgt)
+
+	return nil
+}
+
+func (g *graph) ReplaceEdges(edges []Edge) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if err := g.unsafeCheckFrozen(); err != nil {
+		return err
+	}
+
+	keyed := make([]Edge, len(edges))
+	for i, e := range edges {
+		from, to := g.cfg.key(e.From), g.cfg.key(e.To)
+		if !g.unsafeIdExist(from) {
+			return fmt.Errorf("edge %s -> %s: %s does not exist in graph", e.From, e.To, from)
+		}
+
+		if !g.unsafeIdExist(to) {
+			return fmt.Errorf("edge %s -> %s: %s does not exist in graph", e.From, e.To, to)
 		}
+
+		if err := g.cfg.validateWeight(e.Weight); err != nil {
+			return fmt.Errorf("edge %s -> %s: %w", e.From, e.To, err)
+		}
+		keyed[i] = Edge{From: from, To: to, Weight: e.Weight}
 	}
 
-	return nil
+	for _, e := range keyed {
+		g.unsafeSetEdge(e.From, e.To, e.Weight)
+	}
 
+	return nil
 }
 
 func (g *graph) DeleteEdge(id, pid ID) error {
+	id, pid = g.cfg.key(id), g.cfg.key(pid)
+
 	g.mu.Lock()
 	defer g.mu.Unlock()
 
+	if err := g.unsafeCheckFrozen(); err != nil {
+		return err
+	}
+
+	g.st.deleteCalls++
+
 	if !g.unsafeIdExist(id) {
 		return fmt.Errorf("%s does not exist in graph", id)
 	}
@@ -254,22 +1173,14 @@ func (g *graph) DeleteEdge(id, pid ID) error {
 		return fmt.Errorf("%s does not exist in graph", pid)
 	}
 
-	if _, ok := g.nodeTargets[pid]; ok {
-		if _, ok := g.nodeTargets[pid][id]; ok {
-			delete(g.nodeTargets[pid], id)
-		}
-	}
-
-	if _, ok := g.nodeSources[id]; ok {
-		if _, ok := g.nodeSources[id][pid]; ok {
-			delete(g.nodeSources[id], pid)
-		}
-	}
+	g.unsafeRemoveEdge(pid, id)
 
 	return nil
 }
 
 func (g *graph) GetWeight(id, pid ID) (float64, error) {
+	id, pid = g.cfg.key(id), g.cfg.key(pid)
+
 	g.mu.RLock()
 	defer g.mu.RUnlock()
 
@@ -281,17 +1192,71 @@ func (g *graph) GetWeight(id, pid ID) (float64, error) {
 		return 0.0, fmt.Errorf("%s does not exist in graph", pid)
 	}
 
-	if _, ok := g.nodeSources[id]; ok {
-		if w, ok := g.nodeSources[id][pid]; ok {
-			return w, nil
-		}
+	return g.unsafeEdgeWeight(pid, id)
+}
+
+func (g *graph) EdgeWeight(from, to ID) (float64, error) {
+	from, to = g.cfg.key(from), g.cfg.key(to)
+
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	if !g.unsafeIdExist(from) {
+		return 0.0, fmt.Errorf("%s does not exist in graph", from)
+	}
+
+	if !g.unsafeIdExist(to) {
+		return 0.0, fmt.Errorf("%s does not exist in graph", to)
 	}
 
-	return 0.0, fmt.Errorf("there is no edge from %s to %s", pid, id)
+	return g.unsafeEdgeWeight(from, to)
+}
+
+// 返回 from -> to 的权重，边不存在时返回 error，调用方需要持有读锁
+func (g *graph) unsafeEdgeWeight(from, to ID) (float64, error) {
+	if w, ok := g.nodeTargets[from][to]; ok {
+		return w, nil
+	}
 
+	return 0.0, fmt.Errorf("no edge from %s to %s", from, to)
+}
+
+func (g *graph) HasEdgeAtLeast(from, to ID, minWeight float64) bool {
+	from, to = g.cfg.key(from), g.cfg.key(to)
+
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	w, ok := g.nodeTargets[from][to]
+
+	return ok && w >= minWeight
+}
+
+func (g *graph) TargetWeight(from, to ID) (float64, bool) {
+	from, to = g.cfg.key(from), g.cfg.key(to)
+
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	w, ok := g.nodeTargets[from][to]
+
+	return w, ok
+}
+
+func (g *graph) SourceWeight(to, from ID) (float64, bool) {
+	to, from = g.cfg.key(to), g.cfg.key(from)
+
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	w, ok := g.nodeSources[to][from]
+
+	return w, ok
 }
 
 func (g *graph) GetSources(id ID) (map[ID]Node, error) {
+	id = g.cfg.key(id)
+
 	g.mu.RLock()
 	defer g.mu.RUnlock()
 
@@ -311,6 +1276,8 @@ func (g *graph) GetSources(id ID) (map[ID]Node, error) {
 }
 
 func (g *graph) GetTargets(pid ID) (map[ID]Node, error) {
+	pid = g.cfg.key(pid)
+
 	g.mu.RLock()
 	defer g.mu.RUnlock()
 
@@ -329,27 +1296,94 @@ func (g *graph) GetTargets(pid ID) (map[ID]Node, error) {
 	return t, nil
 }
 
-func (g *graph) JSON() ([]byte, error) {
+func (g *graph) RangeTargets(id ID, fn func(to ID, weight float64) bool) error {
+	id = g.cfg.key(id)
+
 	g.mu.RLock()
 	defer g.mu.RUnlock()
 
-	rs := make(map[string]map[string]float64)
-	for id, _ := range g.nodeList {
-		smap, err := g.GetSources(id)
-		if err != nil {
-			continue
+	if !g.unsafeIdExist(id) {
+		return fmt.Errorf("%s does not exist in graph", id)
+	}
+
+	for tid, wgt := range g.nodeTargets[id] {
+		if !fn(tid, wgt) {
+			break
 		}
+	}
 
-		for pid, _ := range smap {
-			if wgt, err := g.GetWeight(id, pid); err == nil {
-				if _, ok := rs[id.String()]; ok {
-					rs[id.String()][id.String()] = wgt
-				} else {
-					rs[id.String()] = map[string]float64{pid.String(): wgt}
-				}
-			}
+	return nil
+}
+
+func (g *graph) RangeSources(id ID, fn func(from ID, weight float64) bool) error {
+	id = g.cfg.key(id)
+
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	if !g.unsafeIdExist(id) {
+		return fmt.Errorf("%s does not exist in graph", id)
+	}
+
+	for sid, wgt := range g.nodeSources[id] {
+		if !fn(sid, wgt) {
+			break
+		}
+	}
+
+	return nil
+}
+
+func (g *graph) Neighbors(id ID) (map[ID]Node, error) {
+	id = g.cfg.key(id)
+
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	if !g.unsafeIdExist(id) {
+		return nil, fmt.Errorf("%s does not exist in graph", id)
+	}
+
+	n := make(map[ID]Node)
+	for nid := range g.unsafeNeighborWeights(id) {
+		n[nid] = g.nodeList[nid]
+	}
+
+	return n, nil
+}
+
+func (g *graph) NeighborsWithWeights(id ID) (map[ID]float64, error) {
+	id = g.cfg.key(id)
+
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	if !g.unsafeIdExist(id) {
+		return nil, fmt.Errorf("%s does not exist in graph", id)
+	}
+
+	return g.unsafeNeighborWeights(id), nil
+}
+
+// 以无向图的视角返回 id 的邻居及权重，自环只计算一次
+func (g *graph) unsafeNeighborWeights(id ID) map[ID]float64 {
+	n := make(map[ID]float64, len(g.nodeTargets[id])+len(g.nodeSources[id]))
+	for tid, wgt := range g.nodeTargets[id] {
+		n[tid] += wgt
+	}
+
+	for sid, wgt := range g.nodeSources[id] {
+		if sid != id {
+			n[sid] += wgt
 		}
 	}
-	return ffjson.Marshal(rs)
 
+	return n
+}
+
+func (g *graph) JSON() ([]byte, error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	return ffjson.Marshal(g.unsafeJSONMap())
 }
@@ -1,7 +1,12 @@
 package kraph
 
 import (
+	"errors"
 	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
 )
 
@@ -45,3 +50,464 @@ func TestNewGraph(t *testing.T) {
 	fmt.Println(nd, smap, tmap, num, nodes, wgt, string(j))
 	g.Init()
 }
+
+func TestDeleteNodeWithReport(t *testing.T) {
+	g := NewGraph()
+
+	a, b, c := NewNid("a"), NewNid("b"), NewNid("c")
+	g.AddNode(NewNode(a))
+	g.AddNode(NewNode(b))
+	g.AddNode(NewNode(c))
+
+	// a -> b, b -> c, b -> b, c -> a
+	g.AddEdge(b, a, 1.0)
+	g.AddEdge(c, b, 2.0)
+	g.AddEdge(b, b, 3.0)
+	g.AddEdge(a, c, 4.0)
+
+	removed, ok := g.DeleteNodeWithReport(b)
+	if !ok {
+		t.Fatal("DeleteNodeWithReport returned false for existing node")
+	}
+
+	want := []Edge{
+		{From: a, To: b, Weight: 1.0},
+		{From: b, To: b, Weight: 3.0},
+		{From: b, To: c, Weight: 2.0},
+	}
+	if len(removed) != len(want) {
+		t.Fatalf("removed %v, want %v", removed, want)
+	}
+	for i := range want {
+		if removed[i] != want[i] {
+			t.Errorf("removed[%d] = %v, want %v", i, removed[i], want[i])
+		}
+	}
+
+	if tmap, _ := g.GetTargets(a); len(tmap) != 0 {
+		t.Errorf("a still has targets %v", tmap)
+	}
+	if w, err := g.GetWeight(a, c); err != nil || w != 4.0 {
+		t.Errorf("unrelated edge c -> a lost: %v %v", w, err)
+	}
+
+	if _, ok := g.DeleteNodeWithReport(b); ok {
+		t.Error("DeleteNodeWithReport returned true for missing node")
+	}
+}
+
+func TestDeleteNodes(t *testing.T) {
+	g := buildGraph([]string{"a", "b", "c", "d"}, [][2]string{{"a", "b"}, {"b", "c"}, {"c", "d"}, {"d", "a"}})
+
+	a, b, c, d := NewNid("a"), NewNid("b"), NewNid("c"), NewNid("d")
+	if n := g.DeleteNodes([]ID{a, c, c, NewNid("x")}); n != 2 {
+		t.Errorf("DeleteNodes returned %d, want 2", n)
+	}
+
+	if n := g.GetNodeCount(); n != 2 {
+		t.Errorf("node count = %d, want 2", n)
+	}
+	for _, id := range []ID{b, d} {
+		if nb, _ := g.Neighbors(id); len(nb) != 0 {
+			t.Errorf("%s still has neighbors %v", id, nb)
+		}
+	}
+	if st := g.Stats(); st.Edges != 0 {
+		t.Errorf("stats report %d edges after deletion", st.Edges)
+	}
+}
+
+func TestInOutDegree(t *testing.T) {
+	// a -> b, a -> c, b -> c, c -> c
+	g := buildGraph([]string{"a", "b", "c"}, [][2]string{{"a", "b"}, {"a", "c"}, {"b", "c"}, {"c", "c"}})
+
+	a, b, c := NewNid("a"), NewNid("b"), NewNid("c")
+	check := func(id ID, in, out int) {
+		t.Helper()
+		if d, err := g.InDegree(id); err != nil || d != in {
+			t.Errorf("InDegree(%s) = %d, %v; want %d", id, d, err, in)
+		}
+		if d, err := g.OutDegree(id); err != nil || d != out {
+			t.Errorf("OutDegree(%s) = %d, %v; want %d", id, d, err, out)
+		}
+	}
+
+	check(a, 0, 2)
+	check(b, 1, 1)
+	check(c, 3, 1)
+
+	// 重复添加只修改权重，不改变度数
+	g.AddEdge(b, a, 1)
+	check(b, 1, 1)
+
+	g.DeleteEdge(c, b)
+	check(b, 1, 0)
+	check(c, 2, 1)
+
+	g.DeleteNode(c)
+	check(a, 0, 1)
+	if _, err := g.InDegree(c); err == nil {
+		t.Error("expected error for deleted node")
+	}
+}
+
+func TestSourceNodes(t *testing.T) {
+	// c 有自环，因此不是源点
+	g := buildGraph([]string{"a", "b", "c", "d", "e"}, [][2]string{{"a", "b"}, {"d", "b"}, {"c", "c"}, {"b", "e"}})
+
+	if got := idStrings(g.SourceNodes()); !reflect.DeepEqual(got, []string{"a", "d"}) {
+		t.Errorf("SourceNodes() = %v, want [a d]", got)
+	}
+
+	g.DeleteNode(NewNid("a"))
+	g.DeleteEdge(NewNid("e"), NewNid("b"))
+	if got := idStrings(g.SourceNodes()); !reflect.DeepEqual(got, []string{"d", "e"}) {
+		t.Errorf("SourceNodes() after deletion = %v, want [d e]", got)
+	}
+}
+
+func BenchmarkOutDegree(b *testing.B) {
+	for _, n := range []int{10, 10000} {
+		g := NewGraph()
+		hub := NewNid("hub")
+		g.AddNode(NewNode(hub))
+		for i := 0; i < n; i++ {
+			id := NewNid(strconv.Itoa(i))
+			g.AddNode(NewNode(id))
+			g.AddEdge(id, hub, 1)
+		}
+
+		b.Run(fmt.Sprintf("neighbors=%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				g.OutDegree(hub)
+			}
+		})
+	}
+}
+
+func TestParallelForEachNode(t *testing.T) {
+	nodes := make([]string, 100)
+	for i := range nodes {
+		nodes[i] = strconv.Itoa(i)
+	}
+	g := buildGraph(nodes, [][2]string{{"0", "1"}})
+
+	var mu sync.Mutex
+	seen := make(map[ID]int)
+	g.ParallelForEachNode(4, func(nd Node) {
+		// fn 中调用读方法不会死锁
+		if _, err := g.Neighbors(nd.GetId()); err != nil {
+			t.Error(err)
+		}
+
+		mu.Lock()
+		seen[nd.GetId()]++
+		mu.Unlock()
+	})
+
+	if len(seen) != 100 {
+		t.Errorf("visited %d nodes, want 100", len(seen))
+	}
+	for id, n := range seen {
+		if n != 1 {
+			t.Errorf("%s visited %d times", id, n)
+		}
+	}
+
+	// 没有 node 或 workers 不合法时不会阻塞
+	NewGraph().ParallelForEachNode(0, func(Node) { t.Error("fn called on empty graph") })
+}
+
+func TestNeighbors(t *testing.T) {
+	g := NewGraph()
+
+	a, b, c, d := NewNid("a"), NewNid("b"), NewNid("c"), NewNid("d")
+	for _, id := range []ID{a, b, c, d} {
+		g.AddNode(NewNode(id))
+	}
+
+	// a -> b, b -> a, c -> a
+	g.AddEdge(b, a, 1.0)
+	g.AddEdge(a, b, 2.0)
+	g.AddEdge(a, c, 4.0)
+
+	n, err := g.Neighbors(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(n) != 2 || n[b] == nil || n[c] == nil {
+		t.Errorf("Neighbors(a) = %v", n)
+	}
+
+	w, _ := g.NeighborsWithWeights(a)
+	if w[b] != 3.0 || w[c] != 4.0 || len(w) != 2 {
+		t.Errorf("NeighborsWithWeights(a) = %v", w)
+	}
+
+	// 返回的是副本，修改后不应影响图
+	w[d] = 1.0
+	if w2, _ := g.NeighborsWithWeights(a); len(w2) != 2 {
+		t.Errorf("NeighborsWithWeights exposed internal state: %v", w2)
+	}
+
+	if _, err := g.Neighbors(NewNid("missing")); err == nil {
+		t.Error("Neighbors on missing node should return error")
+	}
+}
+
+type labeledNode struct {
+	id    ID
+	label string
+}
+
+func (n *labeledNode) GetId() ID {
+	return n.id
+}
+
+func TestReplaceNode(t *testing.T) {
+	g := NewGraph()
+
+	a, b := NewNid("a"), NewNid("b")
+	g.AddNode(NewNode(a))
+	g.AddNode(NewNode(b))
+	g.AddEdge(b, a, 2.0)
+
+	if err := g.ReplaceNode(&labeledNode{id: a, label: "upgraded"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if nd, ok := g.GetNode(a).(*labeledNode); !ok || nd.label != "upgraded" {
+		t.Errorf("GetNode(a) = %v", g.GetNode(a))
+	}
+	if w, err := g.GetWeight(b, a); err != nil || w != 2.0 {
+		t.Errorf("edge a -> b lost after ReplaceNode: %v, %v", w, err)
+	}
+
+	if err := g.ReplaceNode(NewNode(NewNid("missing"))); err == nil {
+		t.Error("ReplaceNode on missing node should return error")
+	}
+}
+
+func TestRangeNodes(t *testing.T) {
+	g := NewGraph()
+	for _, id := range []string{"a", "b", "c", "d"} {
+		g.AddNode(NewNode(NewNid(id)))
+	}
+
+	seen := make(map[ID]bool)
+	g.RangeNodes(func(id ID, n Node) bool {
+		if n.GetId() != id {
+			t.Errorf("node %v stored under id %v", n.GetId(), id)
+		}
+		seen[id] = true
+		return true
+	})
+	if len(seen) != 4 {
+		t.Errorf("visited %d nodes, want 4", len(seen))
+	}
+
+	count := 0
+	g.RangeNodes(func(id ID, n Node) bool {
+		count++
+		return count < 2
+	})
+	if count != 2 {
+		t.Errorf("RangeNodes did not stop early, visited %d", count)
+	}
+}
+
+func TestRangeTargetsSources(t *testing.T) {
+	g := NewGraph()
+
+	a, b, c := NewNid("a"), NewNid("b"), NewNid("c")
+	g.AddNode(NewNode(a))
+	g.AddNode(NewNode(b))
+	g.AddNode(NewNode(c))
+	g.AddEdge(b, a, 1.0)
+	g.AddEdge(c, a, 2.0)
+
+	targets := make(map[ID]float64)
+	if err := g.RangeTargets(a, func(to ID, w float64) bool {
+		targets[to] = w
+		return true
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if len(targets) != 2 || targets[b] != 1.0 || targets[c] != 2.0 {
+		t.Errorf("RangeTargets(a) visited %v", targets)
+	}
+
+	sources := make(map[ID]float64)
+	g.RangeSources(c, func(from ID, w float64) bool {
+		sources[from] = w
+		return true
+	})
+	if len(sources) != 1 || sources[a] != 2.0 {
+		t.Errorf("RangeSources(c) visited %v", sources)
+	}
+
+	count := 0
+	g.RangeTargets(a, func(to ID, w float64) bool {
+		count++
+		return false
+	})
+	if count != 1 {
+		t.Errorf("RangeTargets did not stop early, visited %d", count)
+	}
+
+	if err := g.RangeSources(NewNid("missing"), func(ID, float64) bool { return true }); err == nil {
+		t.Error("RangeSources on missing node should return error")
+	}
+}
+
+func TestLookupNode(t *testing.T) {
+	g := NewGraph()
+
+	a := NewNid("a")
+	g.AddNode(NewNode(a))
+
+	if nd, ok := g.LookupNode(a); !ok || nd.GetId() != a {
+		t.Errorf("LookupNode(a) = %v, %v", nd, ok)
+	}
+	if nd, ok := g.LookupNode(NewNid("missing")); ok || nd != nil {
+		t.Errorf("LookupNode(missing) = %v, %v", nd, ok)
+	}
+}
+
+func TestHasEdgeAtLeast(t *testing.T) {
+	g := NewGraph()
+
+	a, b := NewNid("a"), NewNid("b")
+	g.AddNode(NewNode(a))
+	g.AddNode(NewNode(b))
+	g.AddEdge(b, a, 2.0)
+
+	cases := []struct {
+		from, to ID
+		min      float64
+		want     bool
+	}{
+		{a, b, 2.0, true},
+		{a, b, 1.5, true},
+		{a, b, 2.5, false},
+		{b, a, 0, false},
+		{a, NewNid("missing"), 0, false},
+	}
+	for _, cs := range cases {
+		if got := g.HasEdgeAtLeast(cs.from, cs.to, cs.min); got != cs.want {
+			t.Errorf("HasEdgeAtLeast(%s, %s, %v) = %v, want %v", cs.from, cs.to, cs.min, got, cs.want)
+		}
+	}
+}
+
+func TestTargetSourceWeight(t *testing.T) {
+	g := buildGraph([]string{"a", "b"}, [][2]string{{"a", "b"}}, 2.5)
+	a, b := NewNid("a"), NewNid("b")
+
+	if w, ok := g.TargetWeight(a, b); !ok || w != 2.5 {
+		t.Errorf("TargetWeight(a, b) = %v, %v", w, ok)
+	}
+	if w, ok := g.SourceWeight(b, a); !ok || w != 2.5 {
+		t.Errorf("SourceWeight(b, a) = %v, %v", w, ok)
+	}
+
+	if _, ok := g.TargetWeight(b, a); ok {
+		t.Error("TargetWeight(b, a) found a reverse edge")
+	}
+	if _, ok := g.SourceWeight(a, b); ok {
+		t.Error("SourceWeight(a, b) found a reverse edge")
+	}
+	if _, ok := g.TargetWeight(NewNid("x"), a); ok {
+		t.Error("TargetWeight found an edge from a missing node")
+	}
+}
+
+func TestEdgeWeight(t *testing.T) {
+	g := buildGraph([]string{"a", "b"}, [][2]string{{"a", "b"}}, 2.5)
+	a, b := NewNid("a"), NewNid("b")
+
+	if w, err := g.EdgeWeight(a, b); err != nil || w != 2.5 {
+		t.Errorf("EdgeWeight(a, b) = %v, %v", w, err)
+	}
+
+	// GetWeight 的参数顺序是 (终点, 起点)
+	if w, err := g.GetWeight(b, a); err != nil || w != 2.5 {
+		t.Errorf("GetWeight(b, a) = %v, %v", w, err)
+	}
+
+	_, err := g.EdgeWeight(b, a)
+	if err == nil || err.Error() != "no edge from b to a" {
+		t.Errorf("EdgeWeight(b, a) error = %v", err)
+	}
+	if _, err := g.GetWeight(a, b); err == nil || err.Error() != "no edge from b to a" {
+		t.Errorf("GetWeight(a, b) error = %v", err)
+	}
+
+	if _, err := g.EdgeWeight(a, NewNid("x")); err == nil {
+		t.Error("expected error for missing node")
+	}
+}
+
+func TestReplaceEdges(t *testing.T) {
+	g := buildGraph([]string{"a", "b", "c"}, [][2]string{{"a", "b"}, {"b", "c"}}, 1, 2)
+	a, b, c := NewNid("a"), NewNid("b"), NewNid("c")
+
+	err := g.ReplaceEdges([]Edge{
+		{From: a, To: b, Weight: 5},
+		{From: c, To: a, Weight: 7},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if w, _ := g.EdgeWeight(a, b); w != 5 {
+		t.Errorf("a -> b = %v, want 5 (overwritten)", w)
+	}
+	if w, _ := g.EdgeWeight(c, a); w != 7 {
+		t.Errorf("c -> a = %v, want 7 (created)", w)
+	}
+	if w, _ := g.EdgeWeight(b, c); w != 2 {
+		t.Errorf("b -> c = %v, want 2 (unchanged)", w)
+	}
+
+	err = g.ReplaceEdges([]Edge{
+		{From: a, To: b, Weight: 9},
+		{From: a, To: NewNid("x"), Weight: 1},
+	})
+	if err == nil || !strings.Contains(err.Error(), "a -> x") {
+		t.Errorf("expected error naming edge a -> x, got %v", err)
+	}
+	if w, _ := g.EdgeWeight(a, b); w != 5 {
+		t.Errorf("failed ReplaceEdges modified a -> b to %v", w)
+	}
+}
+
+func TestBuildFromEdgeList(t *testing.T) {
+	a, b, c := NewNid("a"), NewNid("b"), NewNid("c")
+	edges := []Edge{
+		{From: a, To: b, Weight: 1},
+		{From: b, To: c, Weight: 2},
+		{From: a, To: b, Weight: 3},
+	}
+
+	g, err := BuildFromEdgeList(edges, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.GetNodeCount() != 3 {
+		t.Errorf("node count = %d", g.GetNodeCount())
+	}
+	if w, _ := g.GetWeight(b, a); w != 4 {
+		t.Errorf("edge a -> b = %v, want 4", w)
+	}
+	if w, _ := g.GetWeight(c, b); w != 2 {
+		t.Errorf("edge b -> c = %v, want 2", w)
+	}
+
+	if _, err := BuildFromEdgeList(edges, false); err == nil || !strings.Contains(err.Error(), "a -> b") {
+		t.Errorf("expected error naming edge a -> b, got %v", err)
+	}
+
+	if _, err := BuildFromEdgeList(edges, true, WithMaxNodes(2)); !errors.Is(err, ErrCapacityExceeded) {
+		t.Errorf("expected ErrCapacityExceeded, got %v", err)
+	}
+}
@@ -0,0 +1,137 @@
+package kraph
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/pquerna/ffjson/ffjson"
+	"strings"
+)
+
+type networkxDoc struct {
+	Directed   bool                   `json:"directed"`
+	Multigraph bool                   `json:"multigraph"`
+	Graph      map[string]interface{} `json:"graph"`
+	Nodes      []networkxNode         `json:"nodes"`
+	Links      []networkxLink         `json:"links"`
+}
+
+type networkxNode struct {
+	ID string `json:"id"`
+}
+
+type networkxLink struct {
+	Source string  `json:"source"`
+	Target string  `json:"target"`
+	Weight float64 `json:"weight"`
+}
+
+func (g *graph) NetworkXJSON() ([]byte, error) {
+	g.mu.RLock()
+	doc := networkxDoc{
+		Directed: true,
+		Graph:    map[string]interface{}{},
+		Nodes:    make([]networkxNode, 0, len(g.nodeList)),
+		Links:    make([]networkxLink, 0),
+	}
+	for _, id := range g.unsafeNodeIDs() {
+		doc.Nodes = append(doc.Nodes, networkxNode{ID: id.String()})
+	}
+	for _, e := range g.unsafeEdges() {
+		doc.Links = append(doc.Links, networkxLink{Source: e.From.String(), Target: e.To.String(), Weight: e.Weight})
+	}
+	g.mu.RUnlock()
+
+	return ffjson.Marshal(doc)
+}
+
+// 读取时 node 的 id 可能是字符串以外的任意 JSON 值，边也可能保存在 edges 字段中（NetworkX 3.4 之后的格式）
+type networkxInput struct {
+	Directed *bool `json:"directed"`
+	Nodes    []struct {
+		ID json.RawMessage `json:"id"`
+	} `json:"nodes"`
+	Links []networkxInputLink `json:"links"`
+	Edges []networkxInputLink `json:"edges"`
+}
+
+type networkxInputLink struct {
+	Source json.RawMessage `json:"source"`
+	Target json.RawMessage `json:"target"`
+	Weight *float64        `json:"weight"`
+}
+
+// 将 JSON 值转换为 node id，字符串直接使用其内容，其它值使用其 JSON 文本
+func networkxID(raw json.RawMessage) (ID, error) {
+	text := strings.TrimSpace(string(raw))
+	if text == "" || text == "null" {
+		return nil, fmt.Errorf("missing node id")
+	}
+
+	if text[0] == '"' {
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return nil, err
+		}
+
+		return NewNid(s), nil
+	}
+
+	return NewNid(text), nil
+}
+
+// LoadNetworkXJSON 从 NetworkX node_link_data 格式的 JSON 中读取 node 和边并构建 graph
+// directed 为 false 时每条边会被拆分成两条方向相反的有向边，没有 weight 的边权重为 1，其它属性会被忽略
+// 非字符串的 node id 使用其 JSON 文本作为 id，例如 1 会被读取为 "1"
+func LoadNetworkXJSON(data []byte, opts ...Option) (Graph, error) {
+	var doc networkxInput
+	if err := ffjson.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+
+	directed := doc.Directed == nil || *doc.Directed
+
+	g := NewGraph(opts...)
+	for _, n := range doc.Nodes {
+		id, err := networkxID(n.ID)
+		if err != nil {
+			return nil, err
+		}
+		g.AddNode(NewNode(id))
+	}
+
+	links := doc.Links
+	if len(links) == 0 {
+		links = doc.Edges
+	}
+
+	for _, l := range links {
+		from, err := networkxID(l.Source)
+		if err != nil {
+			return nil, fmt.Errorf("invalid link source: %v", err)
+		}
+
+		to, err := networkxID(l.Target)
+		if err != nil {
+			return nil, fmt.Errorf("invalid link target: %v", err)
+		}
+
+		wgt := 1.0
+		if l.Weight != nil {
+			wgt = *l.Weight
+		}
+
+		g.AddNode(NewNode(from))
+		g.AddNode(NewNode(to))
+
+		if err := g.AddEdge(to, from, wgt); err != nil {
+			return nil, err
+		}
+		if !directed && from != to {
+			if err := g.AddEdge(from, to, wgt); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return g, nil
+}
@@ -0,0 +1,84 @@
+package kraph
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestNetworkXJSON(t *testing.T) {
+	g := buildGraph(
+		[]string{"a", "b", "c", "d"},
+		[][2]string{{"b", "c"}, {"a", "b"}, {"c", "a"}},
+		2, 1.5, 3,
+	)
+
+	data, err := g.NetworkXJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var doc map[string]interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("invalid JSON %s: %v", data, err)
+	}
+	if doc["directed"] != true {
+		t.Errorf("directed = %v", doc["directed"])
+	}
+	if links := doc["links"].([]interface{}); len(links) != 3 {
+		t.Errorf("links = %v", links)
+	}
+
+	g2, err := LoadNetworkXJSON(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g2.GetNodeCount() != 4 {
+		t.Errorf("round trip node count = %d", g2.GetNodeCount())
+	}
+	for _, e := range g.EdgesSortedByWeight(false) {
+		if w, err := g2.GetWeight(e.To, e.From); err != nil || w != e.Weight {
+			t.Errorf("round trip edge %s -> %s = %v, %v; want %v", e.From, e.To, w, err, e.Weight)
+		}
+	}
+	if s1, s2 := g.String(), g2.String(); s1 != s2 {
+		t.Errorf("round trip changed graph:\n%s\nvs\n%s", s1, s2)
+	}
+}
+
+func TestLoadNetworkXJSON(t *testing.T) {
+	data := `{
+		"directed": false,
+		"multigraph": false,
+		"graph": {"name": "g"},
+		"nodes": [{"id": 1, "color": "red"}, {"id": "x"}, {"id": 3}],
+		"edges": [
+			{"source": 1, "target": "x", "weight": 2.5},
+			{"source": "x", "target": 3}
+		]
+	}`
+
+	g, err := LoadNetworkXJSON([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	one, x, three := NewNid("1"), NewNid("x"), NewNid("3")
+	cases := []struct {
+		from, to ID
+		wgt      float64
+	}{
+		{one, x, 2.5},
+		{x, one, 2.5},
+		{x, three, 1},
+		{three, x, 1},
+	}
+	for _, cs := range cases {
+		if w, err := g.GetWeight(cs.to, cs.from); err != nil || w != cs.wgt {
+			t.Errorf("edge %s -> %s = %v, %v; want %v", cs.from, cs.to, w, err, cs.wgt)
+		}
+	}
+
+	if _, err := LoadNetworkXJSON([]byte(`{"links": [{"target": "a"}]}`)); err == nil {
+		t.Error("expected error for link without source")
+	}
+}
@@ -0,0 +1,156 @@
+package kraph
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// ErrNonFiniteWeight 表示边的权重为 NaN 或无穷大
+var ErrNonFiniteWeight = errors.New("weight must be a finite number")
+
+// ErrCapacityExceeded 表示 node 数量已经达到 WithMaxNodes 设置的上限
+var ErrCapacityExceeded = errors.New("graph node capacity exceeded")
+
+// Option 用于在 NewGraph 时对 graph 进行配置
+type Option func(*config)
+
+type config struct {
+	weightValidator func(float64) error
+	maxNodes        int
+	allowNonFinite  bool
+	aggregator      func(old, new float64) float64
+	caseInsensitive bool
+	insertionOrder  bool
+	panicOnFrozen   bool
+	connectivity    bool
+}
+
+// WithWeightValidator 设置边权重的校验函数
+// AddEdge 和 ReplaceEdge 在写入权重前会调用它，校验失败时直接返回其 error
+func WithWeightValidator(fn func(float64) error) Option {
+	return func(c *config) {
+		c.weightValidator = fn
+	}
+}
+
+// WithMaxNodes 限制图中 node 的最大数量，达到上限后 AddNode 返回 false，AddNodes 返回 ErrCapacityExceeded
+// FilterNodes、Intersection、Union 等方法返回的新图沿用同样的上限，其中只有 Union 可能超出上限，此时返回 ErrCapacityExceeded
+// n 不大于 0 时不做限制
+func WithMaxNodes(n int) Option {
+	return func(c *config) {
+		c.maxNodes = n
+	}
+}
+
+// WithNonFiniteWeights 允许边的权重为 NaN 或无穷大
+// 默认情况下 AddEdge 和 ReplaceEdge 会拒绝这样的权重并返回 ErrNonFiniteWeight
+func WithNonFiniteWeights() Option {
+	return func(c *config) {
+		c.allowNonFinite = true
+	}
+}
+
+// WithEdgeAggregator 设置 AddEdge 遇到已经存在的边时合并权重的方式，old 为已有的权重，new 为新增的权重
+// 不设置时权重相加，ReverseEdge 和 RemoveAndBypass 合并已有的边时同样使用 fn
+func WithEdgeAggregator(fn func(old, new float64) float64) Option {
+	return func(c *config) {
+		c.aggregator = fn
+	}
+}
+
+// WithCaseInsensitiveIDs 将 NewNid 创建的 id 统一转换为小写后再写入或查找，"NodeA" 和 "nodea" 会被视为同一个 node
+// 图中保存和返回的都是小写的 id，其它实现了 ID 接口的类型不受影响
+func WithCaseInsensitiveIDs() Option {
+	return func(c *config) {
+		c.caseInsensitive = true
+	}
+}
+
+// WithInsertionOrder 在 map 之外额外维护一个按添加顺序排列的边列表，通过 EdgesInOrder 读取
+// 修改已有边的权重不会改变它的位置，删除的边会从列表中移除，再次添加时排在最后
+func WithInsertionOrder() Option {
+	return func(c *config) {
+		c.insertionOrder = true
+	}
+}
+
+// WithFrozenPanic 使 Freeze 之后的修改操作直接 panic(ErrFrozen)，而不是返回 ErrFrozen 或 false
+// 适合用来尽早发现下游代码中意外的修改
+func WithFrozenPanic() Option {
+	return func(c *config) {
+		c.panicOnFrozen = true
+	}
+}
+
+// WithIncrementalConnectivity 在添加 node 和边时增量维护一个并查集，使 Connected 的查询接近 O(1)
+// 删除 node 或边之后并查集会失效，下一次 Connected 时按当前的图重新构建，适合以添加为主、很少删除的场景
+func WithIncrementalConnectivity() Option {
+	return func(c *config) {
+		c.connectivity = true
+	}
+}
+
+// 返回 id 在图中使用的 key，所有访问内部 map 的入口都需要先经过它
+func (c *config) key(id ID) ID {
+	if n, ok := id.(nid); ok && c.caseInsensitive {
+		return nid(strings.ToLower(string(n)))
+	}
+
+	return id
+}
+
+// 对 ids 中的每个 id 调用 key，不会修改传入的切片
+func (c *config) keys(ids []ID) []ID {
+	if !c.caseInsensitive {
+		return ids
+	}
+
+	ks := make([]ID, len(ids))
+	for i, id := range ids {
+		ks[i] = c.key(id)
+	}
+
+	return ks
+}
+
+// 对集合中的每个 id 调用 key，不会修改传入的 map
+func (c *config) keySet(set map[ID]bool) map[ID]bool {
+	if !c.caseInsensitive {
+		return set
+	}
+
+	ks := make(map[ID]bool, len(set))
+	for id, ok := range set {
+		ks[c.key(id)] = ks[c.key(id)] || ok
+	}
+
+	return ks
+}
+
+func (c *config) aggregate(old, new float64) float64 {
+	if c.aggregator == nil {
+		return old + new
+	}
+
+	return c.aggregator(old, new)
+}
+
+func (c *config) validateWeight(wgt float64) error {
+	if c.weightValidator != nil {
+		if err := c.weightValidator(wgt); err != nil {
+			return err
+		}
+	}
+
+	return c.checkFinite(wgt)
+}
+
+func (c *config) checkFinite(wgt float64) error {
+	if !c.allowNonFinite && (math.IsNaN(wgt) || math.IsInf(wgt, 0)) {
+		return fmt.Errorf("%w: got %v", ErrNonFiniteWeight, wgt)
+	}
+
+	return nil
+}
@@ -0,0 +1,294 @@
+package kraph
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestWithWeightValidator(t *testing.T) {
+	errInvalid := errors.New("invalid weight")
+	g := NewGraph(WithWeightValidator(func(w float64) error {
+		if math.IsNaN(w) || math.IsInf(w, 0) || w < 0 {
+			return errInvalid
+		}
+		return nil
+	}))
+
+	a, b := NewNid("a"), NewNid("b")
+	g.AddNode(NewNode(a))
+	g.AddNode(NewNode(b))
+
+	if err := g.AddEdge(b, a, 1.0); err != nil {
+		t.Fatalf("AddEdge with valid weight: %v", err)
+	}
+	if err := g.AddEdge(b, a, -1.0); err != errInvalid {
+		t.Errorf("AddEdge with negative weight returned %v", err)
+	}
+	if err := g.ReplaceEdge(b, a, math.NaN()); err != errInvalid {
+		t.Errorf("ReplaceEdge with NaN returned %v", err)
+	}
+	if wgt, _ := g.GetWeight(b, a); wgt != 1.0 {
+		t.Errorf("weight changed by rejected call, got %v", wgt)
+	}
+}
+
+func TestWithWeightValidatorMergedWeight(t *testing.T) {
+	errTooLarge := errors.New("weight too large")
+	validator := WithWeightValidator(func(w float64) error {
+		if w > 10 {
+			return errTooLarge
+		}
+		return nil
+	})
+
+	a, b := NewNid("a"), NewNid("b")
+	g := NewGraph(validator)
+	g.AddNode(NewNode(a))
+	g.AddNode(NewNode(b))
+
+	if err := g.AddEdge(b, a, 6); err != nil {
+		t.Fatal(err)
+	}
+	// 两个 6 都能通过校验，但合并后的 12 不能
+	if err := g.AddEdge(b, a, 6); err != errTooLarge {
+		t.Errorf("AddEdge merged sum returned %v, want errTooLarge", err)
+	}
+	if err := g.AddEdgeIfAcyclic(a, b, 6); err != errTooLarge {
+		t.Errorf("AddEdgeIfAcyclic merged sum returned %v, want errTooLarge", err)
+	}
+	if wgt, _ := g.EdgeWeight(a, b); wgt != 6 {
+		t.Errorf("weight = %v after rejected merges, want 6", wgt)
+	}
+
+	edges := []Edge{{From: a, To: b, Weight: 6}, {From: a, To: b, Weight: 6}}
+	if _, err := BuildFromEdgeList(edges, true, validator); !errors.Is(err, errTooLarge) {
+		t.Errorf("BuildFromEdgeList merged sum returned %v, want errTooLarge", err)
+	}
+
+	tg := NewTypedGraph[int, string](validator)
+	tg.AddNode(NewNode(a))
+	tg.AddNode(NewNode(b))
+	tg.AddEdgeValue(a, b, 6, "x")
+	if err := tg.AddEdgeValue(a, b, 6, "y"); err != errTooLarge {
+		t.Errorf("AddEdgeValue merged sum returned %v, want errTooLarge", err)
+	}
+	if v, _ := tg.GetEdgeValue(a, b); v != "x" {
+		t.Errorf("edge value = %q after rejected merge, want x", v)
+	}
+}
+
+func TestWithMaxNodes(t *testing.T) {
+	g := NewGraph(WithMaxNodes(3))
+
+	if !g.AddNode(NewNode(NewNid("a"))) {
+		t.Fatal("AddNode below capacity returned false")
+	}
+
+	nds := []Node{
+		NewNode(NewNid("a")),
+		NewNode(NewNid("b")),
+		NewNode(NewNid("c")),
+		NewNode(NewNid("d")),
+	}
+	added, err := g.AddNodes(nds)
+	if added != 2 || err != ErrCapacityExceeded {
+		t.Errorf("AddNodes() = %d, %v; want 2, ErrCapacityExceeded", added, err)
+	}
+
+	if g.AddNode(NewNode(NewNid("e"))) {
+		t.Error("AddNode above capacity returned true")
+	}
+	if g.GetNodeCount() != 3 {
+		t.Errorf("node count = %d, want 3", g.GetNodeCount())
+	}
+
+	// 删除后可以继续添加
+	g.DeleteNode(NewNid("a"))
+	if !g.AddNode(NewNode(NewNid("e"))) {
+		t.Error("AddNode after delete returned false")
+	}
+}
+
+func TestWithMaxNodesDerivedGraphs(t *testing.T) {
+	g := NewGraph(WithMaxNodes(3))
+	for _, id := range []string{"a", "b", "c"} {
+		g.AddNode(NewNode(NewNid(id)))
+	}
+
+	// 与本图的 node 完全重合时不会超出上限
+	same := buildGraph([]string{"a", "b"}, [][2]string{{"a", "b"}})
+	if u, err := g.Union(same); err != nil || u.GetNodeCount() != 3 {
+		t.Errorf("Union within capacity = %v, %v", u, err)
+	}
+
+	other := buildGraph([]string{"a", "d"}, [][2]string{{"a", "d"}})
+	if _, err := g.Union(other); err != ErrCapacityExceeded {
+		t.Errorf("Union over capacity = %v, want ErrCapacityExceeded", err)
+	}
+
+	// 派生的图沿用同样的上限
+	fg := g.FilterNodes(func(nd Node) bool { return nd.GetId() != NewNid("c") })
+	if !fg.AddNode(NewNode(NewNid("d"))) {
+		t.Error("AddNode on filtered graph below capacity returned false")
+	}
+	if fg.AddNode(NewNode(NewNid("e"))) {
+		t.Error("AddNode on filtered graph above capacity returned true")
+	}
+}
+
+func TestNonFiniteWeights(t *testing.T) {
+	a, b := NewNid("a"), NewNid("b")
+
+	g := NewGraph()
+	g.AddNode(NewNode(a))
+	g.AddNode(NewNode(b))
+
+	for _, w := range []float64{math.NaN(), math.Inf(1), math.Inf(-1)} {
+		if err := g.AddEdge(b, a, w); !errors.Is(err, ErrNonFiniteWeight) {
+			t.Errorf("AddEdge(%v) returned %v", w, err)
+		}
+		if err := g.ReplaceEdge(b, a, w); !errors.Is(err, ErrNonFiniteWeight) {
+			t.Errorf("ReplaceEdge(%v) returned %v", w, err)
+		}
+	}
+
+	// 相加后溢出同样被拒绝，原有的权重保持不变
+	g.AddEdge(b, a, math.MaxFloat64)
+	if err := g.AddEdge(b, a, math.MaxFloat64); !errors.Is(err, ErrNonFiniteWeight) {
+		t.Errorf("overflowing AddEdge returned %v", err)
+	}
+	if w, _ := g.GetWeight(b, a); w != math.MaxFloat64 {
+		t.Errorf("weight corrupted to %v", w)
+	}
+
+	allow := NewGraph(WithNonFiniteWeights())
+	allow.AddNode(NewNode(a))
+	allow.AddNode(NewNode(b))
+	if err := allow.AddEdge(b, a, math.Inf(1)); err != nil {
+		t.Errorf("AddEdge(+Inf) with WithNonFiniteWeights returned %v", err)
+	}
+}
+
+func TestWithEdgeAggregator(t *testing.T) {
+	a, b := NewNid("a"), NewNid("b")
+
+	cases := []struct {
+		name string
+		opts []Option
+		want float64
+	}{
+		{"default sum", nil, 9},
+		{"max", []Option{WithEdgeAggregator(math.Max)}, 5},
+		{"min", []Option{WithEdgeAggregator(math.Min)}, 1},
+		{"last wins", []Option{WithEdgeAggregator(func(old, new float64) float64 { return new })}, 3},
+	}
+
+	for _, c := range cases {
+		g := NewGraph(c.opts...)
+		g.AddNode(NewNode(a))
+		g.AddNode(NewNode(b))
+
+		for _, w := range []float64{1, 5, 3} {
+			if err := g.AddEdge(b, a, w); err != nil {
+				t.Fatalf("%s: AddEdge: %v", c.name, err)
+			}
+		}
+
+		if w, _ := g.GetWeight(b, a); w != c.want {
+			t.Errorf("%s: weight = %v, want %v", c.name, w, c.want)
+		}
+	}
+}
+
+func TestWithCaseInsensitiveIDs(t *testing.T) {
+	g := NewGraph(WithCaseInsensitiveIDs())
+
+	if !g.AddNode(NewNode(NewNid("NodeA"))) {
+		t.Fatal("AddNode(NodeA) returned false")
+	}
+	if g.AddNode(NewNode(NewNid("nodea"))) {
+		t.Error("AddNode(nodea) should collapse into NodeA")
+	}
+	g.AddNode(NewNode(NewNid("B")))
+
+	if err := g.AddEdge(NewNid("b"), NewNid("NODEA"), 2); err != nil {
+		t.Fatal(err)
+	}
+	if w, err := g.GetWeight(NewNid("B"), NewNid("nodeA")); err != nil || w != 2 {
+		t.Errorf("GetWeight = %v, %v; want 2", w, err)
+	}
+
+	if g.GetNodeCount() != 2 {
+		t.Errorf("node count = %d, want 2", g.GetNodeCount())
+	}
+	if _, ok := g.LookupNode(NewNid("b")); !ok {
+		t.Error("LookupNode(b) failed")
+	}
+	if _, ok := g.GetNodes()[NewNid("nodea")]; !ok {
+		t.Errorf("nodes are not keyed by lower case id: %v", g.GetNodes())
+	}
+
+	path, _, err := g.ShortestPathAvoiding(NewNid("NODEA"), NewNid("b"), nil)
+	if err != nil || !reflect.DeepEqual(idStrings(path), []string{"nodea", "b"}) {
+		t.Errorf("ShortestPathAvoiding = %v, %v", path, err)
+	}
+	if _, _, err := g.ShortestPathAvoiding(NewNid("NODEA"), NewNid("b"), map[ID]bool{NewNid("B"): true}); !errors.Is(err, ErrUnreachable) {
+		t.Errorf("avoid set is not normalized: %v", err)
+	}
+
+	// 与区分大小写的图合并时，other 中的 id 同样被规范化
+	other := buildGraph([]string{"NodeA", "C"}, [][2]string{{"NodeA", "C"}})
+	u, err := g.Union(other)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.GetNodeCount() != 3 {
+		t.Errorf("union node count = %d, want 3", u.GetNodeCount())
+	}
+	if _, err := u.GetWeight(NewNid("c"), NewNid("nodea")); err != nil {
+		t.Errorf("union lost edge nodea -> c: %v", err)
+	}
+
+	if !g.DeleteNode(NewNid("NODEA")) || g.GetNodeCount() != 1 {
+		t.Error("DeleteNode(NODEA) failed")
+	}
+
+	// 默认区分大小写
+	cs := NewGraph()
+	cs.AddNode(NewNode(NewNid("A")))
+	if !cs.AddNode(NewNode(NewNid("a"))) {
+		t.Error("default graph should be case sensitive")
+	}
+}
+
+func TestWithInsertionOrder(t *testing.T) {
+	g := NewGraph(WithInsertionOrder())
+	a, b, c := NewNid("a"), NewNid("b"), NewNid("c")
+	for _, id := range []ID{c, b, a} {
+		g.AddNode(NewNode(id))
+	}
+
+	g.AddEdge(a, c, 1) // c -> a
+	g.AddEdge(b, a, 2) // a -> b
+	g.AddEdge(c, b, 3) // b -> c
+	g.AddEdge(a, c, 4) // 合并权重，位置不变
+	g.DeleteEdge(b, a)
+	g.AddEdge(b, a, 5)
+
+	want := []Edge{{c, a, 5}, {b, c, 3}, {a, b, 5}}
+	if got := g.EdgesInOrder(); !reflect.DeepEqual(got, want) {
+		t.Errorf("EdgesInOrder() = %v, want %v", got, want)
+	}
+
+	g.DeleteNode(c)
+	want = []Edge{{a, b, 5}}
+	if got := g.EdgesInOrder(); !reflect.DeepEqual(got, want) {
+		t.Errorf("EdgesInOrder() after DeleteNode = %v, want %v", got, want)
+	}
+
+	if got := NewGraph().EdgesInOrder(); got != nil {
+		t.Errorf("EdgesInOrder() without option = %v, want nil", got)
+	}
+}
@@ -0,0 +1,77 @@
+package kraph
+
+import "container/heap"
+
+type degreeItem struct {
+	idx    int
+	degree int
+}
+
+// 按度数排序的最小堆，度数相同时按下标排序
+type degreeHeap []degreeItem
+
+func (h degreeHeap) Len() int { return len(h) }
+func (h degreeHeap) Less(i, j int) bool {
+	if h[i].degree != h[j].degree {
+		return h[i].degree < h[j].degree
+	}
+
+	return h[i].idx < h[j].idx
+}
+func (h degreeHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *degreeHeap) Push(x interface{}) { *h = append(*h, x.(degreeItem)) }
+func (h *degreeHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	it := old[n-1]
+	*h = old[:n-1]
+
+	return it
+}
+
+func (g *graph) DegeneracyOrdering() ([]ID, int) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	ids := g.unsafeNodeIDs()
+	index := make(map[ID]int, len(ids))
+	for i, id := range ids {
+		index[id] = i
+	}
+
+	adj := g.unsafeSortedNeighbors(ids)
+	degree := make([]int, len(ids))
+	h := make(degreeHeap, 0, len(ids))
+	for i, id := range ids {
+		degree[i] = len(adj[id])
+		h = append(h, degreeItem{idx: i, degree: degree[i]})
+	}
+	heap.Init(&h)
+
+	removed := make([]bool, len(ids))
+	order := make([]ID, 0, len(ids))
+	k := 0
+	for h.Len() > 0 {
+		it := heap.Pop(&h).(degreeItem)
+		// 堆中可能存在过期的记录
+		if removed[it.idx] || it.degree != degree[it.idx] {
+			continue
+		}
+
+		removed[it.idx] = true
+		order = append(order, ids[it.idx])
+		if it.degree > k {
+			k = it.degree
+		}
+
+		for _, nid := range adj[ids[it.idx]] {
+			j := index[nid]
+			if !removed[j] {
+				degree[j]--
+				heap.Push(&h, degreeItem{idx: j, degree: degree[j]})
+			}
+		}
+	}
+
+	return order, k
+}
@@ -0,0 +1,24 @@
+package kraph
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDegeneracyOrdering(t *testing.T) {
+	// 三角形 a b c，外加挂在 c 上的 d，以及孤立的 e
+	g := buildGraph(
+		[]string{"a", "b", "c", "d", "e"},
+		[][2]string{{"a", "b"}, {"b", "c"}, {"c", "a"}, {"c", "d"}, {"d", "d"}},
+	)
+
+	order, k := g.DegeneracyOrdering()
+	if k != 2 {
+		t.Errorf("degeneracy = %d, want 2", k)
+	}
+
+	want := []string{"e", "d", "a", "b", "c"}
+	if got := idStrings(order); !reflect.DeepEqual(got, want) {
+		t.Errorf("DegeneracyOrdering() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,239 @@
+package kraph
+
+import (
+	"math"
+	"sync"
+)
+
+// 以 CSR 形式保存的入边，用于按 node 拉取上游的得分
+type rankMatrix struct {
+	ids []ID
+	// 第 v 个 node 的入边为 inFrom[inStart[v]:inStart[v+1]]
+	inStart []int
+	inFrom  []int
+	// 上游将自身得分的多少比例分给这条边
+	inShare  []float64
+	dangling []bool
+}
+
+// 将 [0, n) 划分为 workers 段连续的区间，并发地对每段调用 fn
+func parallelChunks(n, workers int, fn func(w, lo, hi int)) {
+	if workers < 1 {
+		workers = 1
+	}
+	if workers > n {
+		workers = n
+	}
+	if n == 0 {
+		return
+	}
+
+	chunk := (n + workers - 1) / workers
+	var wg sync.WaitGroup
+	for w := 0; w*chunk < n; w++ {
+		lo, hi := w*chunk, (w+1)*chunk
+		if hi > n {
+			hi = n
+		}
+
+		wg.Add(1)
+		go func(w, lo, hi int) {
+			defer wg.Done()
+			fn(w, lo, hi)
+		}(w, lo, hi)
+	}
+	wg.Wait()
+}
+
+// 构建入边索引，读取 map 的工作由 workers 个 goroutine 并发完成
+// weighted 为 true 时每条边分得的比例为其权重占上游所有正权重之和的比例，权重不大于 0 的边不分配得分
+func (g *graph) unsafeRankMatrix(workers int, weighted bool) *rankMatrix {
+	ids := g.unsafeNodeIDs()
+	n := len(ids)
+	index := make(map[ID]int, n)
+	for i, id := range ids {
+		index[id] = i
+	}
+
+	m := &rankMatrix{
+		ids:      ids,
+		inStart:  make([]int, n+1),
+		dangling: make([]bool, n),
+	}
+
+	share := make([]float64, n)
+	parallelChunks(n, workers, func(_, lo, hi int) {
+		for i := lo; i < hi; i++ {
+			out := float64(len(g.nodeTargets[ids[i]]))
+			if weighted {
+				out = 0
+				for _, wgt := range g.nodeTargets[ids[i]] {
+					if wgt > 0 {
+						out += wgt
+					}
+				}
+			}
+
+			m.dangling[i] = out == 0
+			if out > 0 {
+				share[i] = 1 / out
+			}
+		}
+	})
+
+	// 每段先各自收集入边，再按顺序拼接
+	from := make([][]int, n)
+	parallelChunks(n, workers, func(_, lo, hi int) {
+		for i := lo; i < hi; i++ {
+			srcs := make([]int, 0, len(g.nodeSources[ids[i]]))
+			for sid := range g.nodeSources[ids[i]] {
+				srcs = append(srcs, index[sid])
+			}
+			from[i] = srcs
+		}
+	})
+
+	for i := 0; i < n; i++ {
+		m.inStart[i+1] = m.inStart[i] + len(from[i])
+	}
+
+	m.inFrom = make([]int, m.inStart[n])
+	m.inShare = make([]float64, m.inStart[n])
+	parallelChunks(n, workers, func(_, lo, hi int) {
+		for i := lo; i < hi; i++ {
+			for k, src := range from[i] {
+				e := m.inStart[i] + k
+				m.inFrom[e] = src
+				m.inShare[e] = share[src]
+				if weighted {
+					m.inShare[e] *= math.Max(g.nodeSources[ids[i]][ids[src]], 0)
+				}
+			}
+		}
+	})
+
+	return m
+}
+
+// 迭代计算 PageRank，workers 个 goroutine 各自负责一段连续的 node
+// teleport 为随机跳转的概率分布，为 nil 时使用均匀分布
+func (m *rankMatrix) run(damping float64, iterations, workers int, teleport []float64) map[ID]float64 {
+	n := len(m.ids)
+	if n == 0 {
+		return make(map[ID]float64)
+	}
+
+	if workers < 1 {
+		workers = 1
+	}
+	if workers > n {
+		workers = n
+	}
+
+	if teleport == nil {
+		teleport = make([]float64, n)
+		for i := range teleport {
+			teleport[i] = 1 / float64(n)
+		}
+	}
+
+	rank := make([]float64, n)
+	next := make([]float64, n)
+	danglingSum := 0.0
+	for i := range rank {
+		rank[i] = 1 / float64(n)
+		if m.dangling[i] {
+			danglingSum += rank[i]
+		}
+	}
+
+	partial := make([]float64, workers)
+
+	for it := 0; it < iterations; it++ {
+		// 随机跳转和没有下游的 node 的得分都按 teleport 分配
+		jump := (1 - damping) + damping*danglingSum
+
+		parallelChunks(n, workers, func(w, lo, hi int) {
+			// 顺便统计新一轮中没有下游的 node 的得分之和
+			ds := 0.0
+			for v := lo; v < hi; v++ {
+				sum := 0.0
+				for e := m.inStart[v]; e < m.inStart[v+1]; e++ {
+					sum += rank[m.inFrom[e]] * m.inShare[e]
+				}
+				next[v] = jump*teleport[v] + damping*sum
+
+				if m.dangling[v] {
+					ds += next[v]
+				}
+			}
+			partial[w] = ds
+		})
+
+		danglingSum = 0
+		for w := range partial {
+			danglingSum += partial[w]
+		}
+		rank, next = next, rank
+	}
+
+	result := make(map[ID]float64, n)
+	for i, id := range m.ids {
+		result[id] = rank[i]
+	}
+
+	return result
+}
+
+func (g *graph) PageRank(damping float64, iterations int) map[ID]float64 {
+	return g.PageRankParallel(damping, iterations, 1)
+}
+
+func (g *graph) PageRankParallel(damping float64, iterations int, workers int) map[ID]float64 {
+	g.mu.RLock()
+	m := g.unsafeRankMatrix(workers, false)
+	g.mu.RUnlock()
+
+	return m.run(damping, iterations, workers, nil)
+}
+
+func (g *graph) WeightedPageRank(damping float64, iterations int) map[ID]float64 {
+	g.mu.RLock()
+	m := g.unsafeRankMatrix(1, true)
+	g.mu.RUnlock()
+
+	return m.run(damping, iterations, 1, nil)
+}
+
+func (g *graph) PersonalizedPageRank(seeds map[ID]float64, damping float64, iterations int) map[ID]float64 {
+	if g.cfg.caseInsensitive {
+		ks := make(map[ID]float64, len(seeds))
+		for id, w := range seeds {
+			ks[g.cfg.key(id)] += w
+		}
+		seeds = ks
+	}
+
+	g.mu.RLock()
+	m := g.unsafeRankMatrix(1, false)
+	g.mu.RUnlock()
+
+	teleport := make([]float64, len(m.ids))
+	total := 0.0
+	for i, id := range m.ids {
+		if w := seeds[id]; w > 0 {
+			teleport[i] = w
+			total += w
+		}
+	}
+
+	if total == 0 {
+		return m.run(damping, iterations, 1, nil)
+	}
+
+	for i := range teleport {
+		teleport[i] /= total
+	}
+
+	return m.run(damping, iterations, 1, teleport)
+}
@@ -0,0 +1,150 @@
+package kraph
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func randomGraph(n, m int, seed int64) Graph {
+	rng := rand.New(rand.NewSource(seed))
+	g := NewGraph()
+	for i := 0; i < n; i++ {
+		g.AddNode(NewNode(NewNid(fmt.Sprintf("n%d", i))))
+	}
+
+	for i := 0; i < m; i++ {
+		from := NewNid(fmt.Sprintf("n%d", rng.Intn(n)))
+		to := NewNid(fmt.Sprintf("n%d", rng.Intn(n)))
+		g.AddEdge(to, from, 1+rng.Float64())
+	}
+
+	return g
+}
+
+func TestPageRank(t *testing.T) {
+	// a -> b, b -> c, c -> a, d 没有下游
+	g := buildGraph(
+		[]string{"a", "b", "c", "d"},
+		[][2]string{{"a", "b"}, {"b", "c"}, {"c", "a"}, {"d", "a"}},
+	)
+
+	pr := g.PageRank(0.85, 100)
+	sum := 0.0
+	for _, v := range pr {
+		sum += v
+	}
+	if math.Abs(sum-1) > 1e-9 {
+		t.Errorf("ranks sum to %v", sum)
+	}
+	if pr[NewNid("a")] <= pr[NewNid("b")] || pr[NewNid("d")] >= pr[NewNid("c")] {
+		t.Errorf("unexpected ranking %v", pr)
+	}
+
+	// d 没有上游，得分只来自随机跳转
+	if want := 0.15 / 4; math.Abs(pr[NewNid("d")]-want) > 1e-9 {
+		t.Errorf("rank of d = %v, want %v", pr[NewNid("d")], want)
+	}
+}
+
+func TestPageRankParallel(t *testing.T) {
+	g := randomGraph(500, 3000, 1)
+
+	seq := g.PageRank(0.85, 50)
+	for _, workers := range []int{2, 3, 8} {
+		par := g.PageRankParallel(0.85, 50, workers)
+		for id, v := range seq {
+			if math.Abs(par[id]-v) > 1e-12 {
+				t.Fatalf("workers=%d: rank of %s = %v, want %v", workers, id, par[id], v)
+			}
+		}
+	}
+}
+
+func BenchmarkPageRankParallel(b *testing.B) {
+	g := randomGraph(100000, 1000000, 1)
+
+	for _, workers := range []int{1, 2, 4} {
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				g.PageRankParallel(0.85, 20, workers)
+			}
+		})
+	}
+}
+
+func TestPersonalizedPageRank(t *testing.T) {
+	// 两条互不相连的链：a -> b -> c 和 x -> y -> z
+	g := buildGraph(
+		[]string{"a", "b", "c", "x", "y", "z"},
+		[][2]string{{"a", "b"}, {"b", "c"}, {"x", "y"}, {"y", "z"}},
+	)
+
+	ppr := g.PersonalizedPageRank(map[ID]float64{NewNid("a"): 2}, 0.85, 100)
+
+	sum := 0.0
+	for _, v := range ppr {
+		sum += v
+	}
+	if math.Abs(sum-1) > 1e-9 {
+		t.Errorf("ranks sum to %v", sum)
+	}
+
+	// 所有得分都来自 a 所在的链
+	for _, id := range []string{"x", "y", "z"} {
+		if ppr[NewNid(id)] != 0 {
+			t.Errorf("rank of %s = %v, want 0", id, ppr[NewNid(id)])
+		}
+	}
+	// c 没有下游，其得分会跳转回 a
+	if ppr[NewNid("a")] <= ppr[NewNid("b")] || ppr[NewNid("b")] <= ppr[NewNid("c")] {
+		t.Errorf("unexpected ranking %v", ppr)
+	}
+
+	// 没有种子时与普通 PageRank 相同
+	uniform := g.PersonalizedPageRank(nil, 0.85, 50)
+	plain := g.PageRank(0.85, 50)
+	for id, v := range plain {
+		if math.Abs(uniform[id]-v) > 1e-12 {
+			t.Errorf("rank of %s = %v, want %v", id, uniform[id], v)
+		}
+	}
+}
+
+func TestWeightedPageRank(t *testing.T) {
+	// a 将得分按 3:1 分给 b 和 c，d 只有一条权重为 0 的边，视为没有下游
+	g := buildGraph(
+		[]string{"a", "b", "c", "d"},
+		[][2]string{{"a", "b"}, {"a", "c"}, {"b", "a"}, {"c", "a"}, {"d", "a"}},
+		3, 1, 1, 1, 0,
+	)
+	a, b, c := NewNid("a"), NewNid("b"), NewNid("c")
+
+	pr := g.WeightedPageRank(0.85, 200)
+	sum := 0.0
+	for _, v := range pr {
+		sum += v
+	}
+	if math.Abs(sum-1) > 1e-9 {
+		t.Errorf("ranks sum to %v", sum)
+	}
+
+	// b 与 c 得到的随机跳转部分相同，差值全部来自 a 多分给 b 的一半得分
+	if diff := pr[b] - pr[c]; math.Abs(diff-0.85*0.5*pr[a]) > 1e-9 {
+		t.Errorf("rank(b) - rank(c) = %v, want %v", diff, 0.85*0.5*pr[a])
+	}
+
+	// 所有边的权重相同时结果与 PageRank 一致
+	u := buildGraph(
+		[]string{"a", "b", "c", "d"},
+		[][2]string{{"a", "b"}, {"a", "c"}, {"b", "c"}, {"c", "a"}},
+		2, 2, 2, 2,
+	)
+	want := u.PageRank(0.85, 50)
+	for id, v := range u.WeightedPageRank(0.85, 50) {
+		if math.Abs(v-want[id]) > 1e-12 {
+			t.Errorf("rank of %s = %v, want %v", id, v, want[id])
+		}
+	}
+}
@@ -0,0 +1,521 @@
+package kraph
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// ErrUnreachable 表示两个 node 之间不存在路径
+var ErrUnreachable = errors.New("target is unreachable")
+
+type edgeKey struct {
+	from, to ID
+}
+
+// 从 from 出发运行 Dijkstra，avoid 中的 node 和 avoidEdges 中的边视为不存在
+// 如果 to 不为 nil，则在确定 to 的距离后提前结束
+func (g *graph) unsafeDijkstra(from, to ID, avoid map[ID]bool, avoidEdges map[edgeKey]bool) (map[ID]float64, map[ID]ID, error) {
+	dist := map[ID]float64{from: 0}
+	prev := make(map[ID]ID)
+	done := make(map[ID]bool)
+
+	f := NewFrontier()
+	f.Push(from, 0)
+	for {
+		cur, cd, ok := f.Pop()
+		if !ok {
+			break
+		}
+		done[cur] = true
+
+		if to != nil && cur == to {
+			break
+		}
+
+		for tid, wgt := range g.nodeTargets[cur] {
+			if wgt < 0 {
+				return nil, nil, fmt.Errorf("negative weight on edge from %s to %s", cur, tid)
+			}
+
+			if done[tid] || avoid[tid] || avoidEdges[edgeKey{cur, tid}] {
+				continue
+			}
+
+			nd := cd + wgt
+			if d, ok := dist[tid]; !ok || nd < d {
+				dist[tid] = nd
+				prev[tid] = cur
+				f.Update(tid, nd)
+			}
+		}
+	}
+
+	return dist, prev, nil
+}
+
+// 根据前驱表还原 from 到 to 的路径，调用方需要保证 to 可达
+func buildPath(prev map[ID]ID, from, to ID) []ID {
+	path, _ := ReconstructPath(prev, from, to)
+
+	return path
+}
+
+// ReconstructPath 从 to 开始沿 prev 中的前驱回溯到 from，返回从 from 到 to 的路径
+// prev 通常来自 ShortestPathTree，回溯中断（或 prev 中存在环）时返回 false，from 与 to 相同时返回只包含 from 的路径
+func ReconstructPath(prev map[ID]ID, from, to ID) ([]ID, bool) {
+	path := []ID{to}
+	for cur := to; cur != from; {
+		p, ok := prev[cur]
+		if !ok || len(path) > len(prev) {
+			return nil, false
+		}
+		cur = p
+		path = append(path, cur)
+	}
+
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+
+	return path, true
+}
+
+func (g *graph) ShortestPathAvoiding(from, to ID, avoid map[ID]bool) ([]ID, float64, error) {
+	from, to = g.cfg.key(from), g.cfg.key(to)
+	avoid = g.cfg.keySet(avoid)
+
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	if !g.unsafeIdExist(from) {
+		return nil, 0, fmt.Errorf("%s does not exist in graph", from)
+	}
+
+	if !g.unsafeIdExist(to) {
+		return nil, 0, fmt.Errorf("%s does not exist in graph", to)
+	}
+
+	if avoid[from] || avoid[to] {
+		return nil, 0, fmt.Errorf("%w: no path from %s to %s", ErrUnreachable, from, to)
+	}
+
+	dist, prev, err := g.unsafeDijkstra(from, to, avoid, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	d, ok := dist[to]
+	if !ok {
+		return nil, 0, fmt.Errorf("%w: no path from %s to %s", ErrUnreachable, from, to)
+	}
+
+	return buildPath(prev, from, to), d, nil
+}
+
+func (g *graph) ShortestPathTree(source ID) (map[ID]float64, map[ID]ID, error) {
+	source = g.cfg.key(source)
+
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	if !g.unsafeIdExist(source) {
+		return nil, nil, fmt.Errorf("%s does not exist in graph", source)
+	}
+
+	return g.unsafeDijkstra(source, nil, nil, nil)
+}
+
+func (g *graph) WidestPath(from, to ID) ([]ID, float64, error) {
+	from, to = g.cfg.key(from), g.cfg.key(to)
+
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	if !g.unsafeIdExist(from) {
+		return nil, 0, fmt.Errorf("%s does not exist in graph", from)
+	}
+
+	if !g.unsafeIdExist(to) {
+		return nil, 0, fmt.Errorf("%s does not exist in graph", to)
+	}
+
+	// Frontier 是最小堆，以容量的相反数作为优先级，每次取出当前容量最大的 node
+	width := map[ID]float64{from: math.Inf(1)}
+	prev := make(map[ID]ID)
+	done := make(map[ID]bool)
+
+	f := NewFrontier()
+	f.Push(from, math.Inf(-1))
+	for {
+		cur, _, ok := f.Pop()
+		if !ok || cur == to {
+			break
+		}
+		done[cur] = true
+
+		for tid, wgt := range g.nodeTargets[cur] {
+			if done[tid] {
+				continue
+			}
+
+			nw := math.Min(width[cur], wgt)
+			if w, ok := width[tid]; !ok || nw > w {
+				width[tid] = nw
+				prev[tid] = cur
+				f.Update(tid, -nw)
+			}
+		}
+	}
+
+	w, ok := width[to]
+	if !ok {
+		return nil, 0, fmt.Errorf("%w: no path from %s to %s", ErrUnreachable, from, to)
+	}
+
+	return buildPath(prev, from, to), w, nil
+}
+
+func (g *graph) ReachableWithinBudget(from ID, budget float64) (map[ID]float64, error) {
+	from = g.cfg.key(from)
+
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	if !g.unsafeIdExist(from) {
+		return nil, fmt.Errorf("%s does not exist in graph", from)
+	}
+
+	if budget < 0 {
+		return nil, fmt.Errorf("budget must not be negative, got %v", budget)
+	}
+
+	dist := map[ID]float64{from: 0}
+	done := make(map[ID]bool)
+
+	f := NewFrontier()
+	f.Push(from, 0)
+	for {
+		cur, cd, ok := f.Pop()
+		if !ok {
+			break
+		}
+		done[cur] = true
+
+		for tid, wgt := range g.nodeTargets[cur] {
+			if wgt < 0 {
+				return nil, fmt.Errorf("negative weight on edge from %s to %s", cur, tid)
+			}
+
+			// 超出预算的 node 不会进入 dist，也就不会从它继续扩展
+			nd := cd + wgt
+			if done[tid] || nd > budget {
+				continue
+			}
+
+			if d, ok := dist[tid]; !ok || nd < d {
+				dist[tid] = nd
+				f.Update(tid, nd)
+			}
+		}
+	}
+
+	return dist, nil
+}
+
+// Path 表示一条路径及其总权重
+type Path struct {
+	Nodes  []ID
+	Weight float64
+}
+
+func samePrefix(a, b []ID, n int) bool {
+	if len(a) < n || len(b) < n {
+		return false
+	}
+
+	for i := 0; i < n; i++ {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (g *graph) KShortestPaths(from, to ID, k int) ([]Path, error) {
+	from, to = g.cfg.key(from), g.cfg.key(to)
+
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	if !g.unsafeIdExist(from) {
+		return nil, fmt.Errorf("%s does not exist in graph", from)
+	}
+
+	if !g.unsafeIdExist(to) {
+		return nil, fmt.Errorf("%s does not exist in graph", to)
+	}
+
+	if k < 1 {
+		return nil, fmt.Errorf("k must be positive, got %d", k)
+	}
+
+	dist, prev, err := g.unsafeDijkstra(from, to, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := dist[to]; !ok {
+		return nil, fmt.Errorf("%w: no path from %s to %s", ErrUnreachable, from, to)
+	}
+
+	paths := []Path{{Nodes: buildPath(prev, from, to), Weight: dist[to]}}
+	candidates := make([]Path, 0)
+
+	for len(paths) < k {
+		last := paths[len(paths)-1].Nodes
+		rootWgt := 0.0
+
+		for i := 0; i < len(last)-1; i++ {
+			spur := last[i]
+			if i > 0 {
+				rootWgt += g.nodeTargets[last[i-1]][spur]
+			}
+
+			// 删除所有与当前路径共享前缀的已知路径的下一条边
+			avoidEdges := make(map[edgeKey]bool)
+			for _, p := range paths {
+				if samePrefix(p.Nodes, last, i+1) && len(p.Nodes) > i+1 {
+					avoidEdges[edgeKey{p.Nodes[i], p.Nodes[i+1]}] = true
+				}
+			}
+
+			// 前缀上的 node 不能再次出现，保证路径无环
+			avoid := make(map[ID]bool, i)
+			for _, id := range last[:i] {
+				avoid[id] = true
+			}
+
+			sdist, sprev, err := g.unsafeDijkstra(spur, to, avoid, avoidEdges)
+			if err != nil {
+				return nil, err
+			}
+
+			if _, ok := sdist[to]; !ok {
+				continue
+			}
+
+			nodes := make([]ID, 0, len(last))
+			nodes = append(nodes, last[:i]...)
+			nodes = append(nodes, buildPath(sprev, spur, to)...)
+			cand := Path{Nodes: nodes, Weight: rootWgt + sdist[to]}
+
+			dup := false
+			for _, c := range candidates {
+				if len(c.Nodes) == len(cand.Nodes) && samePrefix(c.Nodes, cand.Nodes, len(cand.Nodes)) {
+					dup = true
+					break
+				}
+			}
+			if !dup {
+				candidates = append(candidates, cand)
+			}
+		}
+
+		if len(candidates) == 0 {
+			break
+		}
+
+		// 取出权重最小的候选路径，权重相同时取较短的路径
+		best := 0
+		for i, c := range candidates {
+			b := candidates[best]
+			if c.Weight < b.Weight || (c.Weight == b.Weight && len(c.Nodes) < len(b.Nodes)) {
+				best = i
+			}
+		}
+		paths = append(paths, candidates[best])
+		candidates = append(candidates[:best], candidates[best+1:]...)
+	}
+
+	return paths, nil
+}
+
+// 双向 Dijkstra 中某一个方向的搜索状态
+type dijkstraSide struct {
+	adj  map[ID]map[ID]float64
+	dist map[ID]float64
+	prev map[ID]ID
+	done map[ID]bool
+	f    *Frontier
+}
+
+func newDijkstraSide(start ID, adj map[ID]map[ID]float64) *dijkstraSide {
+	s := &dijkstraSide{
+		adj:  adj,
+		dist: map[ID]float64{start: 0},
+		prev: make(map[ID]ID),
+		done: make(map[ID]bool),
+		f:    NewFrontier(),
+	}
+	s.f.Push(start, 0)
+
+	return s
+}
+
+// 返回堆顶的距离，堆为空时返回 false
+func (s *dijkstraSide) top() (float64, bool) {
+	_, d, ok := s.f.Peek()
+
+	return d, ok
+}
+
+func (g *graph) ShortestPathBidirectional(from, to ID) ([]ID, float64, error) {
+	from, to = g.cfg.key(from), g.cfg.key(to)
+
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	if !g.unsafeIdExist(from) {
+		return nil, 0, fmt.Errorf("%s does not exist in graph", from)
+	}
+
+	if !g.unsafeIdExist(to) {
+		return nil, 0, fmt.Errorf("%s does not exist in graph", to)
+	}
+
+	if from == to {
+		return []ID{from}, 0, nil
+	}
+
+	fwd := newDijkstraSide(from, g.nodeTargets)
+	bwd := newDijkstraSide(to, g.nodeSources)
+
+	var meet ID
+	best := 0.0
+	found := false
+
+	for {
+		tf, okf := fwd.top()
+		tb, okb := bwd.top()
+		if !okf || !okb {
+			break
+		}
+
+		// 两个方向的堆顶之和不小于已知的最短路径时，已经不可能找到更短的路径
+		if found && tf+tb >= best {
+			break
+		}
+
+		cur, other := fwd, bwd
+		if tb < tf {
+			cur, other = bwd, fwd
+		}
+
+		id, cd, _ := cur.f.Pop()
+		cur.done[id] = true
+
+		for nid, wgt := range cur.adj[id] {
+			if wgt < 0 {
+				return nil, 0, fmt.Errorf("negative weight on edge between %s and %s", id, nid)
+			}
+
+			nd := cd + wgt
+			if d, ok := cur.dist[nid]; !cur.done[nid] && (!ok || nd < d) {
+				cur.dist[nid] = nd
+				cur.prev[nid] = id
+				cur.f.Update(nid, nd)
+			}
+
+			if od, ok := other.dist[nid]; ok {
+				if total := cur.dist[nid] + od; !found || total < best {
+					best = total
+					meet = nid
+					found = true
+				}
+			}
+		}
+	}
+
+	if !found {
+		return nil, 0, fmt.Errorf("%w: no path from %s to %s", ErrUnreachable, from, to)
+	}
+
+	// 前半段沿正向前驱还原，后半段沿反向前驱走到 to
+	path := buildPath(fwd.prev, from, meet)
+	for cur := meet; cur != to; {
+		cur = bwd.prev[cur]
+		path = append(path, cur)
+	}
+
+	return path, best, nil
+}
+
+// ErrNegativeCycle 表示图中存在总权重为负的环，最短路径没有定义
+var ErrNegativeCycle = errors.New("graph contains a negative cycle")
+
+// 以一个连接所有 node 且权重为 0 的虚拟起点运行 Bellman-Ford，返回每个 node 的势能
+func (g *graph) unsafeBellmanFordPotential() (map[ID]float64, error) {
+	h := make(map[ID]float64, len(g.nodeList))
+	for id := range g.nodeList {
+		h[id] = 0
+	}
+
+	// 加上虚拟起点共有 |V|+1 个 node，最多 |V| 轮松弛之后还需要一轮确认没有变化，空图也会在第一轮返回
+	for i := 0; i <= len(g.nodeList); i++ {
+		changed := false
+		for from, tmap := range g.nodeTargets {
+			for to, wgt := range tmap {
+				if h[from]+wgt < h[to] {
+					h[to] = h[from] + wgt
+					changed = true
+				}
+			}
+		}
+
+		if !changed {
+			return h, nil
+		}
+	}
+
+	return nil, ErrNegativeCycle
+}
+
+func (g *graph) AllPairsJohnson() (map[ID]map[ID]float64, error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	h, err := g.unsafeBellmanFordPotential()
+	if err != nil {
+		return nil, err
+	}
+
+	// 重新赋权后所有边的权重都不为负，浮点误差产生的极小负数截断为 0
+	rg := g.unsafeCopyNodes()
+	for from, tmap := range g.nodeTargets {
+		for to, wgt := range tmap {
+			w := wgt + h[from] - h[to]
+			if w < 0 {
+				w = 0
+			}
+			rg.unsafeSetEdge(from, to, w)
+		}
+	}
+
+	result := make(map[ID]map[ID]float64, len(g.nodeList))
+	for from := range g.nodeList {
+		dist, _, err := rg.unsafeDijkstra(from, nil, nil, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		row := make(map[ID]float64, len(dist))
+		for to, d := range dist {
+			row[to] = d - h[from] + h[to]
+		}
+		result[from] = row
+	}
+
+	return result, nil
+}
@@ -0,0 +1,307 @@
+package kraph
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestShortestPathAvoiding(t *testing.T) {
+	// a -> b -> d 是最短路径，a -> c -> d 是备选路径
+	g := buildGraph(
+		[]string{"a", "b", "c", "d", "e"},
+		[][2]string{{"a", "b"}, {"b", "d"}, {"a", "c"}, {"c", "d"}},
+		1.0, 1.0, 2.0, 3.0,
+	)
+	a, b, c, d, e := NewNid("a"), NewNid("b"), NewNid("c"), NewNid("d"), NewNid("e")
+
+	path, w, err := g.ShortestPathAvoiding(a, d, nil)
+	if err != nil || w != 2.0 || !reflect.DeepEqual(idStrings(path), []string{"a", "b", "d"}) {
+		t.Errorf("ShortestPathAvoiding(a, d, nil) = %v, %v, %v", path, w, err)
+	}
+
+	path, w, err = g.ShortestPathAvoiding(a, d, map[ID]bool{b: true})
+	if err != nil || w != 5.0 || !reflect.DeepEqual(idStrings(path), []string{"a", "c", "d"}) {
+		t.Errorf("ShortestPathAvoiding(a, d, {b}) = %v, %v, %v", path, w, err)
+	}
+
+	_, _, err = g.ShortestPathAvoiding(a, d, map[ID]bool{b: true, c: true})
+	if !errors.Is(err, ErrUnreachable) {
+		t.Errorf("expected ErrUnreachable, got %v", err)
+	}
+
+	if _, _, err = g.ShortestPathAvoiding(a, e, nil); !errors.Is(err, ErrUnreachable) {
+		t.Errorf("expected ErrUnreachable for isolated node, got %v", err)
+	}
+
+	if path, w, err = g.ShortestPathAvoiding(a, a, nil); err != nil || w != 0 || len(path) != 1 {
+		t.Errorf("ShortestPathAvoiding(a, a, nil) = %v, %v, %v", path, w, err)
+	}
+}
+
+func TestShortestPathTree(t *testing.T) {
+	g := buildGraph(
+		[]string{"a", "b", "c", "d", "e"},
+		[][2]string{{"a", "b"}, {"b", "d"}, {"a", "c"}, {"c", "d"}, {"b", "c"}},
+		1.0, 5.0, 4.0, 1.0, 1.0,
+	)
+	a := NewNid("a")
+
+	dist, prev, err := g.ShortestPathTree(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantDist := map[string]float64{"a": 0, "b": 1, "c": 2, "d": 3}
+	gotDist := make(map[string]float64)
+	for id, d := range dist {
+		gotDist[id.String()] = d
+	}
+	if !reflect.DeepEqual(gotDist, wantDist) {
+		t.Errorf("dist = %v, want %v", gotDist, wantDist)
+	}
+
+	if path, ok := ReconstructPath(prev, a, NewNid("d")); !ok || !reflect.DeepEqual(idStrings(path), []string{"a", "b", "c", "d"}) {
+		t.Errorf("path to d = %v, %v", path, ok)
+	}
+	if _, ok := prev[a]; ok {
+		t.Error("source has a predecessor")
+	}
+
+	if _, _, err := g.ShortestPathTree(NewNid("x")); err == nil {
+		t.Error("expected error for missing source")
+	}
+}
+
+func TestReconstructPath(t *testing.T) {
+	a, b, c, d := NewNid("a"), NewNid("b"), NewNid("c"), NewNid("d")
+	prev := map[ID]ID{b: a, c: b}
+
+	if path, ok := ReconstructPath(prev, a, c); !ok || !reflect.DeepEqual(idStrings(path), []string{"a", "b", "c"}) {
+		t.Errorf("ReconstructPath(a, c) = %v, %v", path, ok)
+	}
+	if path, ok := ReconstructPath(prev, a, a); !ok || !reflect.DeepEqual(idStrings(path), []string{"a"}) {
+		t.Errorf("ReconstructPath(a, a) = %v, %v", path, ok)
+	}
+	if path, ok := ReconstructPath(prev, a, d); ok {
+		t.Errorf("ReconstructPath(a, d) = %v, want unreachable", path)
+	}
+	if path, ok := ReconstructPath(prev, b, d); ok {
+		t.Errorf("ReconstructPath(b, d) = %v, want unreachable", path)
+	}
+
+	// prev 中存在环时不会无限循环
+	if _, ok := ReconstructPath(map[ID]ID{b: c, c: b}, a, b); ok {
+		t.Error("ReconstructPath on cyclic prev returned true")
+	}
+}
+
+func TestKShortestPaths(t *testing.T) {
+	// 经典的 Yen 算法示例
+	g := buildGraph(
+		[]string{"C", "D", "E", "F", "G", "H"},
+		[][2]string{
+			{"C", "D"}, {"C", "E"}, {"D", "F"}, {"E", "D"}, {"E", "F"},
+			{"E", "G"}, {"F", "G"}, {"F", "H"}, {"G", "H"},
+		},
+		3, 2, 4, 1, 2, 3, 2, 1, 2,
+	)
+
+	paths, err := g.KShortestPaths(NewNid("C"), NewNid("H"), 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []struct {
+		nodes []string
+		wgt   float64
+	}{
+		{[]string{"C", "E", "F", "H"}, 5},
+		{[]string{"C", "E", "G", "H"}, 7},
+		{[]string{"C", "D", "F", "H"}, 8},
+	}
+	if len(paths) != len(want) {
+		t.Fatalf("got %d paths: %v", len(paths), paths)
+	}
+	for i, w := range want {
+		if paths[i].Weight != w.wgt || !reflect.DeepEqual(idStrings(paths[i].Nodes), w.nodes) {
+			t.Errorf("path %d = %v (%v), want %v (%v)", i, idStrings(paths[i].Nodes), paths[i].Weight, w.nodes, w.wgt)
+		}
+	}
+
+	// 路径不足 k 条时返回所有路径
+	all, err := g.KShortestPaths(NewNid("C"), NewNid("H"), 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 1; i < len(all); i++ {
+		if all[i].Weight < all[i-1].Weight {
+			t.Errorf("paths not sorted: %v", all)
+		}
+	}
+	if len(all) != 7 {
+		t.Errorf("expected 7 loopless paths, got %d", len(all))
+	}
+
+	if _, err := g.KShortestPaths(NewNid("H"), NewNid("C"), 1); !errors.Is(err, ErrUnreachable) {
+		t.Errorf("expected ErrUnreachable, got %v", err)
+	}
+}
+
+func TestShortestPathBidirectional(t *testing.T) {
+	g := buildGraph(
+		[]string{"C", "D", "E", "F", "G", "H", "X"},
+		[][2]string{
+			{"C", "D"}, {"C", "E"}, {"D", "F"}, {"E", "D"}, {"E", "F"},
+			{"E", "G"}, {"F", "G"}, {"F", "H"}, {"G", "H"},
+		},
+		3, 2, 4, 1, 2, 3, 2, 1, 2,
+	)
+
+	ids := []string{"C", "D", "E", "F", "G", "H", "X"}
+	for _, f := range ids {
+		for _, to := range ids {
+			from, target := NewNid(f), NewNid(to)
+			_, w1, err1 := g.ShortestPathAvoiding(from, target, nil)
+			path, w2, err2 := g.ShortestPathBidirectional(from, target)
+
+			if (err1 == nil) != (err2 == nil) {
+				t.Errorf("%s -> %s: errors differ: %v vs %v", f, to, err1, err2)
+				continue
+			}
+			if err2 != nil {
+				if !errors.Is(err2, ErrUnreachable) {
+					t.Errorf("%s -> %s: unexpected error %v", f, to, err2)
+				}
+				continue
+			}
+			if w1 != w2 {
+				t.Errorf("%s -> %s: weight %v, want %v", f, to, w2, w1)
+			}
+
+			// 校验路径确实存在且权重一致
+			if path[0] != from || path[len(path)-1] != target {
+				t.Errorf("%s -> %s: bad endpoints %v", f, to, path)
+			}
+			sum := 0.0
+			for i := 1; i < len(path); i++ {
+				w, err := g.GetWeight(path[i], path[i-1])
+				if err != nil {
+					t.Fatalf("%s -> %s: path %v uses missing edge", f, to, path)
+				}
+				sum += w
+			}
+			if sum != w2 {
+				t.Errorf("%s -> %s: path %v sums to %v, want %v", f, to, path, sum, w2)
+			}
+		}
+	}
+}
+
+func TestAllPairsJohnson(t *testing.T) {
+	// 包含负权重但没有负权环
+	g := buildGraph(
+		[]string{"a", "b", "c", "d", "e"},
+		[][2]string{{"a", "b"}, {"b", "c"}, {"a", "c"}, {"c", "d"}, {"d", "b"}},
+		4, -2, 3, 2, 1,
+	)
+
+	dist, err := g.AllPairsJohnson()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		from, to string
+		want     float64
+	}{
+		{"a", "a", 0},
+		{"a", "b", 4},
+		{"a", "c", 2},
+		{"a", "d", 4},
+		{"d", "c", -1},
+		{"c", "b", 3},
+	}
+	for _, cs := range cases {
+		d, ok := dist[NewNid(cs.from)][NewNid(cs.to)]
+		if !ok || math.Abs(d-cs.want) > 1e-9 {
+			t.Errorf("dist[%s][%s] = %v, %v; want %v", cs.from, cs.to, d, ok, cs.want)
+		}
+	}
+
+	if _, ok := dist[NewNid("a")][NewNid("e")]; ok {
+		t.Error("unreachable pair should be omitted")
+	}
+
+	g.ReplaceEdge(NewNid("b"), NewNid("d"), -5)
+	if _, err := g.AllPairsJohnson(); !errors.Is(err, ErrNegativeCycle) {
+		t.Errorf("expected ErrNegativeCycle, got %v", err)
+	}
+
+	// 空图没有环，结果为空
+	dist, err = NewGraph().AllPairsJohnson()
+	if err != nil || len(dist) != 0 {
+		t.Errorf("AllPairsJohnson on empty graph = %v, %v; want empty, nil", dist, err)
+	}
+}
+
+func TestWidestPath(t *testing.T) {
+	// a -> b -> d 的瓶颈为 3，a -> c -> e -> d 的瓶颈为 4
+	g := buildGraph(
+		[]string{"a", "b", "c", "d", "e", "f"},
+		[][2]string{{"a", "b"}, {"b", "d"}, {"a", "c"}, {"c", "e"}, {"e", "d"}, {"a", "d"}},
+		10, 3, 5, 4, 8, 1,
+	)
+	a, d := NewNid("a"), NewNid("d")
+
+	path, w, err := g.WidestPath(a, d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := idStrings(path); !reflect.DeepEqual(got, []string{"a", "c", "e", "d"}) || w != 4 {
+		t.Errorf("WidestPath(a, d) = %v, %v; want [a c e d], 4", got, w)
+	}
+
+	if path, w, err := g.WidestPath(a, a); err != nil || len(path) != 1 || !math.IsInf(w, 1) {
+		t.Errorf("WidestPath(a, a) = %v, %v, %v", path, w, err)
+	}
+
+	if _, _, err := g.WidestPath(a, NewNid("f")); !errors.Is(err, ErrUnreachable) {
+		t.Errorf("expected ErrUnreachable, got %v", err)
+	}
+	if _, _, err := g.WidestPath(a, NewNid("x")); err == nil {
+		t.Error("expected error for missing node")
+	}
+}
+
+func TestReachableWithinBudget(t *testing.T) {
+	// a -> b -> c 的总权重为 4，a -> c 直接相连权重为 6，c -> d 权重为 3
+	g := buildGraph(
+		[]string{"a", "b", "c", "d", "e"},
+		[][2]string{{"a", "b"}, {"b", "c"}, {"a", "c"}, {"c", "d"}, {"e", "a"}},
+		1, 3, 6, 3, 1,
+	)
+
+	got, err := g.ReachableWithinBudget(NewNid("a"), 5)
+	want := map[ID]float64{NewNid("a"): 0, NewNid("b"): 1, NewNid("c"): 4}
+	if err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("ReachableWithinBudget(a, 5) = %v, %v; want %v", got, err, want)
+	}
+
+	if got, _ := g.ReachableWithinBudget(NewNid("a"), 7); len(got) != 4 {
+		t.Errorf("ReachableWithinBudget(a, 7) = %v, want 4 nodes", got)
+	}
+
+	if _, err := g.ReachableWithinBudget(NewNid("a"), -1); err == nil {
+		t.Error("expected error for negative budget")
+	}
+	if _, err := g.ReachableWithinBudget(NewNid("x"), 1); err == nil {
+		t.Error("expected error for missing node")
+	}
+
+	g.AddEdge(NewNid("d"), NewNid("b"), -1)
+	if _, err := g.ReachableWithinBudget(NewNid("a"), 5); err == nil {
+		t.Error("expected error for negative weight")
+	}
+}
@@ -0,0 +1,49 @@
+package kraph
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+func (g *graph) SampleTarget(id ID, rng *rand.Rand) (ID, error) {
+	id = g.cfg.key(id)
+
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	if !g.unsafeIdExist(id) {
+		return nil, fmt.Errorf("%s does not exist in graph", id)
+	}
+
+	// 排序后再抽样，保证相同的随机种子得到相同的结果
+	targets := make([]ID, 0, len(g.nodeTargets[id]))
+	total := 0.0
+	for tid, wgt := range g.nodeTargets[id] {
+		if wgt > 0 {
+			targets = append(targets, tid)
+			total += wgt
+		}
+	}
+
+	if len(targets) == 0 {
+		return nil, fmt.Errorf("%s has no targets to sample", id)
+	}
+	sortIDs(targets)
+
+	var r float64
+	if rng != nil {
+		r = rng.Float64() * total
+	} else {
+		r = rand.Float64() * total
+	}
+
+	for _, tid := range targets {
+		r -= g.nodeTargets[id][tid]
+		if r < 0 {
+			return tid, nil
+		}
+	}
+
+	// 浮点误差可能导致 r 恰好没有减到 0 以下
+	return targets[len(targets)-1], nil
+}
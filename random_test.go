@@ -0,0 +1,47 @@
+package kraph
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestSampleTarget(t *testing.T) {
+	g := buildGraph(
+		[]string{"a", "b", "c", "d"},
+		[][2]string{{"a", "b"}, {"a", "c"}, {"a", "d"}},
+		1, 3, 0,
+	)
+
+	rng := rand.New(rand.NewSource(7))
+	counts := make(map[string]int)
+	const n = 20000
+	for i := 0; i < n; i++ {
+		id, err := g.SampleTarget(NewNid("a"), rng)
+		if err != nil {
+			t.Fatal(err)
+		}
+		counts[id.String()]++
+	}
+
+	if counts["d"] != 0 {
+		t.Errorf("zero-weight target sampled %d times", counts["d"])
+	}
+	if ratio := float64(counts["c"]) / n; math.Abs(ratio-0.75) > 0.02 {
+		t.Errorf("c sampled with ratio %v, want about 0.75", ratio)
+	}
+
+	// 相同的种子得到相同的序列
+	r1, r2 := rand.New(rand.NewSource(1)), rand.New(rand.NewSource(1))
+	for i := 0; i < 10; i++ {
+		x, _ := g.SampleTarget(NewNid("a"), r1)
+		y, _ := g.SampleTarget(NewNid("a"), r2)
+		if x != y {
+			t.Fatalf("samples differ with the same seed: %v vs %v", x, y)
+		}
+	}
+
+	if _, err := g.SampleTarget(NewNid("b"), rng); err == nil {
+		t.Error("expected error for node without targets")
+	}
+}
@@ -0,0 +1,190 @@
+package kraph
+
+import "fmt"
+
+// 创建一个与 g 配置相同、包含 g 中所有 node 但没有边的新图
+func (g *graph) unsafeCopyNodes() *graph {
+	ng := newGraph(g.cfg)
+	for _, nd := range g.nodeList {
+		ng.unsafeAddNode(nd)
+	}
+
+	return ng
+}
+
+func (g *graph) Complement(wgt float64) (Graph, error) {
+	if err := g.cfg.validateWeight(wgt); err != nil {
+		return nil, err
+	}
+
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	ng := g.unsafeCopyNodes()
+	for from := range g.nodeList {
+		for to := range g.nodeList {
+			if from == to {
+				continue
+			}
+
+			if _, ok := g.nodeTargets[from][to]; !ok {
+				ng.unsafeSetEdge(from, to, wgt)
+			}
+		}
+	}
+
+	return ng, nil
+}
+
+// 深拷贝 g 的所有 node 和边，node 本身是共享的
+func (g *graph) unsafeClone() *graph {
+	ng := g.unsafeCopyNodes()
+	for from, tmap := range g.nodeTargets {
+		for to, wgt := range tmap {
+			ng.unsafeSetEdge(from, to, wgt)
+		}
+	}
+
+	return ng
+}
+
+// 如果 cfg 要求忽略 id 的大小写而 g 没有，则返回按照 cfg 的规则重新生成 key 的拷贝，否则直接返回 g
+// 重新生成 key 后重复的边权重相加，用于合并 id 规则不同的两个图
+func (g *graph) unsafeRekey(cfg config) *graph {
+	if !cfg.caseInsensitive || g.cfg.caseInsensitive {
+		return g
+	}
+
+	ng := newGraph(config{caseInsensitive: true})
+	for id, nd := range g.nodeList {
+		if !ng.unsafeIdExist(ng.cfg.key(id)) {
+			ng.unsafeAddNode(nd)
+		}
+	}
+
+	for from, tmap := range g.nodeTargets {
+		for to, wgt := range tmap {
+			kf, kt := ng.cfg.key(from), ng.cfg.key(to)
+			if w, ok := ng.nodeTargets[kf][kt]; ok {
+				wgt += w
+			}
+			ng.unsafeSetEdge(kf, kt, wgt)
+		}
+	}
+
+	return ng
+}
+
+// 获取任意 Graph 实现的一份私有拷贝，之后读取它不需要再加锁
+func snapshotGraph(gr Graph) *graph {
+	if og, ok := gr.(*graph); ok {
+		og.mu.RLock()
+		defer og.mu.RUnlock()
+
+		return og.unsafeClone()
+	}
+
+	ng := newGraph(config{})
+	nodes := gr.GetNodes()
+	for _, nd := range nodes {
+		ng.unsafeAddNode(nd)
+	}
+
+	for from := range nodes {
+		tmap, err := gr.GetTargets(from)
+		if err != nil {
+			continue
+		}
+
+		for to := range tmap {
+			if wgt, err := gr.GetWeight(to, from); err == nil {
+				ng.unsafeSetEdge(from, to, wgt)
+			}
+		}
+	}
+
+	return ng
+}
+
+func (g *graph) Union(other Graph) (Graph, error) {
+	// 先拷贝 other，避免同时持有两个图的锁
+	og := snapshotGraph(other).unsafeRekey(g.cfg)
+
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	// 新图沿用本图的配置，合并后的 node 数量同样不能超过 WithMaxNodes 的上限
+	ng := g.unsafeClone()
+	for id, nd := range og.nodeList {
+		if ng.unsafeIdExist(id) {
+			continue
+		}
+		if ng.unsafeFull() {
+			return nil, ErrCapacityExceeded
+		}
+		ng.unsafeAddNode(nd)
+	}
+
+	// 两个有限的权重相加后也可能溢出为无穷大，other 的配置也可能比本图宽松
+	for from, tmap := range og.nodeTargets {
+		for to, wgt := range tmap {
+			if w, ok := ng.nodeTargets[from][to]; ok {
+				wgt += w
+			}
+			if err := ng.cfg.validateWeight(wgt); err != nil {
+				return nil, fmt.Errorf("edge %s -> %s: %w", from, to, err)
+			}
+			ng.unsafeSetEdge(from, to, wgt)
+		}
+	}
+
+	return ng, nil
+}
+
+func (g *graph) Intersection(other Graph) Graph {
+	og := snapshotGraph(other).unsafeRekey(g.cfg)
+
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	ng := newGraph(g.cfg)
+	for id, nd := range g.nodeList {
+		if og.unsafeIdExist(id) {
+			ng.unsafeAddNode(nd)
+		}
+	}
+
+	for from, tmap := range g.nodeTargets {
+		for to, wgt := range tmap {
+			ow, ok := og.nodeTargets[from][to]
+			if !ok {
+				continue
+			}
+
+			if ow < wgt {
+				wgt = ow
+			}
+			ng.unsafeSetEdge(from, to, wgt)
+		}
+	}
+
+	return ng
+}
+
+func (g *graph) Difference(other Graph) Graph {
+	og := snapshotGraph(other).unsafeRekey(g.cfg)
+
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	ng := g.unsafeCopyNodes()
+	for from, tmap := range g.nodeTargets {
+		for to, wgt := range tmap {
+			if _, ok := og.nodeTargets[from][to]; !ok {
+				ng.unsafeSetEdge(from, to, wgt)
+			}
+		}
+	}
+
+	return ng
+}
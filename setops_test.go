@@ -0,0 +1,207 @@
+package kraph
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestComplement(t *testing.T) {
+	g := buildGraph(
+		[]string{"a", "b", "c"},
+		[][2]string{{"a", "b"}, {"b", "c"}, {"a", "a"}},
+	)
+
+	cg, err := g.Complement(0.5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cg.GetNodeCount() != 3 {
+		t.Fatalf("node count = %d", cg.GetNodeCount())
+	}
+
+	want := map[[2]string]bool{
+		{"a", "c"}: true,
+		{"b", "a"}: true,
+		{"c", "a"}: true,
+		{"c", "b"}: true,
+	}
+	for _, from := range []string{"a", "b", "c"} {
+		tmap, _ := cg.GetTargets(NewNid(from))
+		for to := range tmap {
+			if !want[[2]string{from, to.String()}] {
+				t.Errorf("unexpected edge %s -> %s", from, to)
+			}
+			if w, _ := cg.GetWeight(to, NewNid(from)); w != 0.5 {
+				t.Errorf("edge %s -> %s weight = %v", from, to, w)
+			}
+			delete(want, [2]string{from, to.String()})
+		}
+	}
+	if len(want) != 0 {
+		t.Errorf("missing edges %v", want)
+	}
+
+	// 原图不应被修改
+	if tmap, _ := g.GetTargets(NewNid("c")); len(tmap) != 0 {
+		t.Errorf("original graph mutated: %v", tmap)
+	}
+
+	// 补图的权重同样需要通过校验
+	if _, err := g.Complement(math.NaN()); !errors.Is(err, ErrNonFiniteWeight) {
+		t.Errorf("Complement(NaN) = %v, want ErrNonFiniteWeight", err)
+	}
+	errNegative := errors.New("negative weight")
+	vg := NewGraph(WithWeightValidator(func(w float64) error {
+		if w < 0 {
+			return errNegative
+		}
+		return nil
+	}))
+	if _, err := vg.Complement(-1); err != errNegative {
+		t.Errorf("Complement(-1) with validator = %v, want errNegative", err)
+	}
+}
+
+func TestUnionIntersection(t *testing.T) {
+	g1 := buildGraph(
+		[]string{"a", "b", "c"},
+		[][2]string{{"a", "b"}, {"b", "c"}},
+		1.0, 2.0,
+	)
+	g2 := buildGraph(
+		[]string{"b", "c", "d"},
+		[][2]string{{"b", "c"}, {"c", "d"}},
+		5.0, 3.0,
+	)
+
+	u, err := g1.Union(g2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.GetNodeCount() != 4 {
+		t.Errorf("union node count = %d", u.GetNodeCount())
+	}
+	unionWant := map[[2]string]float64{
+		{"a", "b"}: 1.0,
+		{"b", "c"}: 7.0,
+		{"c", "d"}: 3.0,
+	}
+	for e, want := range unionWant {
+		if w, err := u.GetWeight(NewNid(e[1]), NewNid(e[0])); err != nil || w != want {
+			t.Errorf("union edge %v = %v, %v; want %v", e, w, err, want)
+		}
+	}
+
+	in := g1.Intersection(g2)
+	if in.GetNodeCount() != 2 {
+		t.Errorf("intersection node count = %d", in.GetNodeCount())
+	}
+	if w, err := in.GetWeight(NewNid("c"), NewNid("b")); err != nil || w != 2.0 {
+		t.Errorf("intersection edge b -> c = %v, %v", w, err)
+	}
+	if _, err := in.GetWeight(NewNid("d"), NewNid("c")); err == nil {
+		t.Error("intersection should not contain c -> d")
+	}
+
+	// 输入不应被修改
+	if w, _ := g1.GetWeight(NewNid("c"), NewNid("b")); w != 2.0 {
+		t.Errorf("g1 mutated: b -> c = %v", w)
+	}
+
+	// 与自身做并集不应死锁
+	if self, err := g1.Union(g1); err != nil || self.GetNodeCount() != 3 {
+		t.Errorf("self union node count = %d", self.GetNodeCount())
+	}
+}
+
+func TestUnionValidatesWeights(t *testing.T) {
+	big := math.MaxFloat64
+	g1 := buildGraph([]string{"a", "b"}, [][2]string{{"a", "b"}}, big)
+	g2 := buildGraph([]string{"a", "b"}, [][2]string{{"a", "b"}}, big)
+
+	// 两个有限的权重相加后溢出为 +Inf
+	if _, err := g1.Union(g2); !errors.Is(err, ErrNonFiniteWeight) {
+		t.Errorf("Union overflow: got %v, want ErrNonFiniteWeight", err)
+	}
+
+	errTooHeavy := errors.New("too heavy")
+	g3 := NewGraph(WithWeightValidator(func(w float64) error {
+		if w > 5 {
+			return errTooHeavy
+		}
+		return nil
+	}))
+	g3.AddNode(NewNode(NewNid("a")))
+	g3.AddNode(NewNode(NewNid("b")))
+	g3.AddEdge(NewNid("b"), NewNid("a"), 3)
+	g4 := buildGraph([]string{"a", "b"}, [][2]string{{"a", "b"}}, 4)
+	if _, err := g3.Union(g4); !errors.Is(err, errTooHeavy) {
+		t.Errorf("Union over validator bound: got %v, want errTooHeavy", err)
+	}
+}
+
+func TestDifference(t *testing.T) {
+	lastWeek := buildGraph(
+		[]string{"a", "b", "c"},
+		[][2]string{{"a", "b"}, {"b", "c"}},
+		1.0, 2.0,
+	)
+	thisWeek := buildGraph(
+		[]string{"a", "b", "c"},
+		[][2]string{{"b", "c"}},
+		9.0,
+	)
+
+	d := lastWeek.Difference(thisWeek)
+	if d.GetNodeCount() != 3 {
+		t.Errorf("node count = %d", d.GetNodeCount())
+	}
+	if w, err := d.GetWeight(NewNid("b"), NewNid("a")); err != nil || w != 1.0 {
+		t.Errorf("edge a -> b = %v, %v", w, err)
+	}
+	if _, err := d.GetWeight(NewNid("c"), NewNid("b")); err == nil {
+		t.Error("edge b -> c should have been subtracted")
+	}
+}
+
+func TestReplaceContents(t *testing.T) {
+	g := buildGraph([]string{"a", "b"}, [][2]string{{"a", "b"}})
+	next := buildGraph([]string{"x", "y", "z"}, [][2]string{{"x", "y"}, {"y", "z"}}, 2, 3)
+
+	if err := g.ReplaceContents(next); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := g.LookupNode(NewNid("a")); ok {
+		t.Error("old node a still present")
+	}
+	if w, _ := g.GetWeight(NewNid("z"), NewNid("y")); w != 3 {
+		t.Errorf("edge y -> z = %v, want 3", w)
+	}
+	if st := g.Stats(); st.Nodes != 3 || st.Edges != 2 || st.MaxDegree != 2 {
+		t.Errorf("stats after replace = %+v", st)
+	}
+
+	// 深拷贝：修改 next 不影响 g
+	next.DeleteNode(NewNid("x"))
+	if _, err := g.GetWeight(NewNid("y"), NewNid("x")); err != nil {
+		t.Error("g shares state with the replaced source")
+	}
+
+	if err := g.ReplaceContents(g); err != nil || g.GetNodeCount() != 3 {
+		t.Errorf("replacing with itself = %v, %d nodes", err, g.GetNodeCount())
+	}
+
+	small := NewGraph(WithMaxNodes(2))
+	small.AddNode(NewNode(NewNid("keep")))
+	if err := small.ReplaceContents(next); err != nil {
+		t.Errorf("ReplaceContents within capacity = %v", err)
+	}
+	if err := small.ReplaceContents(g); !errors.Is(err, ErrCapacityExceeded) {
+		t.Errorf("expected ErrCapacityExceeded, got %v", err)
+	}
+	if small.GetNodeCount() != 2 {
+		t.Errorf("failed replace modified graph: %d nodes", small.GetNodeCount())
+	}
+}
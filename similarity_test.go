@@ -0,0 +1,107 @@
+package kraph
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestCommonNeighbors(t *testing.T) {
+	// a -> c, a -> d, b -> c, e -> b, e -> a
+	g := buildGraph(
+		[]string{"a", "b", "c", "d", "e"},
+		[][2]string{{"a", "c"}, {"a", "d"}, {"b", "c"}, {"e", "b"}, {"e", "a"}},
+	)
+	a, b := NewNid("a"), NewNid("b")
+
+	got, err := g.CommonNeighbors(a, b, false)
+	if err != nil || !reflect.DeepEqual(idStrings(got), []string{"c"}) {
+		t.Errorf("CommonNeighbors(a, b, false) = %v, %v", got, err)
+	}
+
+	got, err = g.CommonNeighbors(a, b, true)
+	if err != nil || !reflect.DeepEqual(idStrings(got), []string{"c", "e"}) {
+		t.Errorf("CommonNeighbors(a, b, true) = %v, %v", got, err)
+	}
+
+	if _, err := g.CommonNeighbors(a, NewNid("x"), false); err == nil {
+		t.Error("expected error for missing node")
+	}
+}
+
+func TestJaccardSimilarity(t *testing.T) {
+	g := buildGraph(
+		[]string{"a", "b", "c", "d", "e", "f", "g"},
+		[][2]string{{"a", "c"}, {"a", "d"}, {"b", "c"}, {"e", "b"}, {"e", "a"}},
+	)
+
+	// N(a) = {c, d, e}，N(b) = {c, e}
+	if j, err := g.JaccardSimilarity(NewNid("a"), NewNid("b")); err != nil || j != 2.0/3 {
+		t.Errorf("JaccardSimilarity(a, b) = %v, %v; want 2/3", j, err)
+	}
+
+	if j, err := g.JaccardSimilarity(NewNid("f"), NewNid("g")); err != nil || j != 0 {
+		t.Errorf("JaccardSimilarity of isolated nodes = %v, %v; want 0", j, err)
+	}
+
+	if _, err := g.JaccardSimilarity(NewNid("x"), NewNid("a")); err == nil {
+		t.Error("expected error for missing node")
+	}
+}
+
+func TestSimRank(t *testing.T) {
+	// a、b 只有共同的上游 u，d 的上游为 u 和 v
+	g := buildGraph(
+		[]string{"a", "b", "d", "u", "v"},
+		[][2]string{{"u", "a"}, {"u", "b"}, {"u", "d"}, {"v", "d"}},
+	)
+	a, b, d := NewNid("a"), NewNid("b"), NewNid("d")
+
+	got := g.SimRank(0.8, 5, 0)
+	want := map[[2]ID]float64{{a, b}: 0.8, {a, d}: 0.4, {b, d}: 0.4}
+	if len(got) != len(want) {
+		t.Fatalf("SimRank() = %v, want %v", got, want)
+	}
+	for k, w := range want {
+		if math.Abs(got[k]-w) > 1e-9 {
+			t.Errorf("SimRank()[%v] = %v, want %v", k, got[k], w)
+		}
+	}
+
+	if got := g.SimRank(0.8, 5, 0.5); len(got) != 1 {
+		t.Errorf("SimRank() with threshold 0.5 = %v, want only (a, b)", got)
+	}
+
+	if g.SimRank(1, 5, 0) != nil || g.SimRank(0.8, -1, 0) != nil {
+		t.Error("expected nil for invalid parameters")
+	}
+}
+
+func TestCosineSimilarity(t *testing.T) {
+	// a = (x:1, y:2)，b = (x:2, y:4)，c = (z:3)
+	g := buildGraph(
+		[]string{"a", "b", "c", "d", "x", "y", "z"},
+		[][2]string{{"a", "x"}, {"a", "y"}, {"b", "x"}, {"b", "y"}, {"c", "z"}, {"d", "x"}, {"d", "z"}},
+		1, 2, 2, 4, 3, 1, 1,
+	)
+	a, b, c, d := NewNid("a"), NewNid("b"), NewNid("c"), NewNid("d")
+
+	cases := []struct {
+		x, y ID
+		want float64
+	}{
+		{a, b, 1},
+		{a, c, 0},
+		{a, d, 1 / (math.Sqrt(5) * math.Sqrt(2))},
+		{a, NewNid("x"), 0},
+	}
+	for _, cs := range cases {
+		if got, err := g.CosineSimilarity(cs.x, cs.y); err != nil || math.Abs(got-cs.want) > 1e-12 {
+			t.Errorf("CosineSimilarity(%s, %s) = %v, %v; want %v", cs.x, cs.y, got, err, cs.want)
+		}
+	}
+
+	if _, err := g.CosineSimilarity(a, NewNid("q")); err == nil {
+		t.Error("expected error for missing node")
+	}
+}
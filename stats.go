@@ -0,0 +1,76 @@
+package kraph
+
+// GraphStats 是图的运行指标快照
+type GraphStats struct {
+	// 当前 node 数量
+	Nodes int
+	// 当前边的数量
+	Edges int
+	// AddEdge 被调用的总次数
+	TotalAddEdgeCalls uint64
+	// DeleteNode 和 DeleteEdge 被调用的总次数
+	TotalDeleteCalls uint64
+	// 当前所有 node 中最大的度数（入度与出度之和）
+	MaxDegree int
+}
+
+// 在 graph 的锁保护下增量维护的计数
+type stats struct {
+	edges        int
+	addEdgeCalls uint64
+	deleteCalls  uint64
+	// 度数 -> 该度数的 node 数量，用于在删除边时维护最大度数
+	degreeCount map[int]int
+	maxDegree   int
+}
+
+func newStats() stats {
+	return stats{
+		degreeCount: make(map[int]int),
+	}
+}
+
+// 重置与图内容相关的计数，调用次数的累计值保持不变
+func (s *stats) reset() {
+	s.edges = 0
+	s.degreeCount = make(map[int]int)
+	s.maxDegree = 0
+}
+
+// 记录一个 node 的度数从 from 变为 to，-1 表示 node 不存在
+func (s *stats) moveDegree(from, to int) {
+	if from == to {
+		return
+	}
+
+	if from >= 0 {
+		s.degreeCount[from]--
+		if s.degreeCount[from] == 0 {
+			delete(s.degreeCount, from)
+		}
+	}
+
+	if to >= 0 {
+		s.degreeCount[to]++
+		if to > s.maxDegree {
+			s.maxDegree = to
+		}
+	}
+
+	for s.maxDegree > 0 && s.degreeCount[s.maxDegree] == 0 {
+		s.maxDegree--
+	}
+}
+
+func (g *graph) Stats() GraphStats {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	return GraphStats{
+		Nodes:             len(g.nodeList),
+		Edges:             g.st.edges,
+		TotalAddEdgeCalls: g.st.addEdgeCalls,
+		TotalDeleteCalls:  g.st.deleteCalls,
+		MaxDegree:         g.st.maxDegree,
+	}
+}
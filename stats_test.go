@@ -0,0 +1,38 @@
+package kraph
+
+import "testing"
+
+func TestStats(t *testing.T) {
+	g := NewGraph()
+
+	a, b, c := NewNid("a"), NewNid("b"), NewNid("c")
+	g.AddNode(NewNode(a))
+	g.AddNode(NewNode(b))
+	g.AddNode(NewNode(c))
+
+	// a -> b, a -> c, a -> b (累加), b -> c, a -> a
+	g.AddEdge(b, a, 1.0)
+	g.AddEdge(c, a, 1.0)
+	g.AddEdge(b, a, 1.0)
+	g.AddEdge(c, b, 1.0)
+	g.AddEdge(a, a, 1.0)
+
+	want := GraphStats{Nodes: 3, Edges: 4, TotalAddEdgeCalls: 5, TotalDeleteCalls: 0, MaxDegree: 4}
+	if got := g.Stats(); got != want {
+		t.Errorf("Stats() = %+v, want %+v", got, want)
+	}
+
+	g.DeleteEdge(a, a)
+	g.DeleteNode(a)
+
+	want = GraphStats{Nodes: 2, Edges: 1, TotalAddEdgeCalls: 5, TotalDeleteCalls: 2, MaxDegree: 1}
+	if got := g.Stats(); got != want {
+		t.Errorf("Stats() after delete = %+v, want %+v", got, want)
+	}
+
+	g.Init()
+	want = GraphStats{Nodes: 0, Edges: 0, TotalAddEdgeCalls: 5, TotalDeleteCalls: 2, MaxDegree: 0}
+	if got := g.Stats(); got != want {
+		t.Errorf("Stats() after Init = %+v, want %+v", got, want)
+	}
+}
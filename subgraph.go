@@ -0,0 +1,132 @@
+package kraph
+
+import "fmt"
+
+func (g *graph) FilterNodes(keep func(Node) bool) Graph {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	ng := newGraph(g.cfg)
+	for _, nd := range g.nodeList {
+		if keep(nd) {
+			ng.unsafeAddNode(nd)
+		}
+	}
+
+	for from, tmap := range g.nodeTargets {
+		if !ng.unsafeIdExist(from) {
+			continue
+		}
+
+		for to, wgt := range tmap {
+			if ng.unsafeIdExist(to) {
+				ng.unsafeSetEdge(from, to, wgt)
+			}
+		}
+	}
+
+	return ng
+}
+
+func (g *graph) FilterEdges(keep func(from, to ID, w float64) bool) Graph {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	ng := g.unsafeCopyNodes()
+	for from, tmap := range g.nodeTargets {
+		for to, wgt := range tmap {
+			if keep(from, to, wgt) {
+				ng.unsafeSetEdge(from, to, wgt)
+			}
+		}
+	}
+
+	return ng
+}
+
+func (g *graph) SpanningForest() (Graph, []ID) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	ids := g.unsafeNodeIDs()
+	adj := g.unsafeSortedNeighbors(ids)
+	ng := g.unsafeCopyNodes()
+	roots := make([]ID, 0)
+
+	seen := make(map[ID]bool, len(ids))
+	for _, root := range ids {
+		if seen[root] {
+			continue
+		}
+		seen[root] = true
+		roots = append(roots, root)
+
+		queue := []ID{root}
+		for len(queue) > 0 {
+			cur := queue[0]
+			queue = queue[1:]
+
+			for _, nid := range adj[cur] {
+				if seen[nid] {
+					continue
+				}
+				seen[nid] = true
+				queue = append(queue, nid)
+
+				if wgt, ok := g.nodeTargets[cur][nid]; ok {
+					ng.unsafeSetEdge(cur, nid, wgt)
+				} else {
+					ng.unsafeSetEdge(nid, cur, g.nodeTargets[nid][cur])
+				}
+			}
+		}
+	}
+
+	return ng, roots
+}
+
+func (g *graph) ReachableSubgraph(roots []ID) (Graph, error) {
+	roots = g.cfg.keys(roots)
+
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	for _, id := range roots {
+		if !g.unsafeIdExist(id) {
+			return nil, fmt.Errorf("%s does not exist in graph", id)
+		}
+	}
+
+	reached := g.unsafeBFS(roots)
+	ng := newGraph(g.cfg)
+	for id := range reached {
+		ng.unsafeAddNode(g.nodeList[id])
+	}
+
+	for from := range reached {
+		for to, wgt := range g.nodeTargets[from] {
+			ng.unsafeSetEdge(from, to, wgt)
+		}
+	}
+
+	return ng, nil
+}
+
+func (g *graph) CollapseMultiEdges(combine func(weights []float64) float64) (Graph, error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	ng := g.unsafeCopyNodes()
+	// 没有平行边的存储，每条边单独重新计算权重，以后支持平行边时在这里收集同一方向上的所有权重
+	for from, tmap := range g.nodeTargets {
+		for to, wgt := range tmap {
+			nw := combine([]float64{wgt})
+			if err := ng.cfg.validateWeight(nw); err != nil {
+				return nil, fmt.Errorf("edge %s -> %s: %w", from, to, err)
+			}
+			ng.unsafeSetEdge(from, to, nw)
+		}
+	}
+
+	return ng, nil
+}
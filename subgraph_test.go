@@ -0,0 +1,175 @@
+package kraph
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFilterNodes(t *testing.T) {
+	g := buildGraph(
+		[]string{"a", "b", "c"},
+		[][2]string{{"a", "b"}, {"b", "c"}, {"c", "a"}},
+		1.0, 2.0, 3.0,
+	)
+
+	sub := g.FilterNodes(func(n Node) bool {
+		return n.GetId().String() != "c"
+	})
+
+	if sub.GetNodeCount() != 2 {
+		t.Errorf("node count = %d", sub.GetNodeCount())
+	}
+	if w, err := sub.GetWeight(NewNid("b"), NewNid("a")); err != nil || w != 1.0 {
+		t.Errorf("edge a -> b = %v, %v", w, err)
+	}
+	if tmap, _ := sub.GetTargets(NewNid("b")); len(tmap) != 0 {
+		t.Errorf("b should have no targets, got %v", tmap)
+	}
+
+	if g.GetNodeCount() != 3 {
+		t.Error("original graph mutated")
+	}
+}
+
+func TestFilterEdges(t *testing.T) {
+	g := buildGraph(
+		[]string{"a", "b", "c"},
+		[][2]string{{"a", "b"}, {"b", "c"}, {"c", "a"}},
+		1.0, 2.0, 3.0,
+	)
+
+	// 以 from 决定的阈值过滤
+	threshold := map[string]float64{"a": 0.5, "b": 5.0, "c": 2.0}
+	sub := g.FilterEdges(func(from, to ID, w float64) bool {
+		return w >= threshold[from.String()]
+	})
+
+	if sub.GetNodeCount() != 3 {
+		t.Errorf("node count = %d", sub.GetNodeCount())
+	}
+	if _, err := sub.GetWeight(NewNid("b"), NewNid("a")); err != nil {
+		t.Errorf("edge a -> b should be kept: %v", err)
+	}
+	if _, err := sub.GetWeight(NewNid("c"), NewNid("b")); err == nil {
+		t.Error("edge b -> c should be dropped")
+	}
+	if w, _ := sub.GetWeight(NewNid("a"), NewNid("c")); w != 3.0 {
+		t.Errorf("edge c -> a = %v", w)
+	}
+
+	if _, err := g.GetWeight(NewNid("c"), NewNid("b")); err != nil {
+		t.Error("original graph mutated")
+	}
+}
+
+func TestCollapseMultiEdges(t *testing.T) {
+	g := buildGraph(
+		[]string{"a", "b", "c"},
+		[][2]string{{"a", "b"}, {"b", "a"}, {"b", "c"}},
+		1.0, 2.0, 3.0,
+	)
+
+	calls := 0
+	collapsed, err := g.CollapseMultiEdges(func(weights []float64) float64 {
+		calls++
+		if len(weights) != 1 {
+			t.Errorf("combine got %v", weights)
+		}
+
+		return weights[0] * 10
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if calls != 3 {
+		t.Errorf("combine called %d times, want 3", calls)
+	}
+	if collapsed.GetNodeCount() != 3 {
+		t.Errorf("node count = %d", collapsed.GetNodeCount())
+	}
+	if w, _ := collapsed.GetWeight(NewNid("a"), NewNid("b")); w != 20 {
+		t.Errorf("edge b -> a = %v, want 20", w)
+	}
+	if w, _ := g.GetWeight(NewNid("a"), NewNid("b")); w != 2 {
+		t.Error("original graph mutated")
+	}
+
+	// combine 返回的权重同样需要通过校验
+	nan := func([]float64) float64 { return math.NaN() }
+	if _, err := g.CollapseMultiEdges(nan); !errors.Is(err, ErrNonFiniteWeight) {
+		t.Errorf("CollapseMultiEdges with NaN combiner = %v, want ErrNonFiniteWeight", err)
+	}
+	errTooLarge := errors.New("weight too large")
+	vg := NewGraph(WithWeightValidator(func(w float64) error {
+		if w > 10 {
+			return errTooLarge
+		}
+		return nil
+	}))
+	vg.AddNode(NewNode(NewNid("a")))
+	vg.AddNode(NewNode(NewNid("b")))
+	vg.AddEdge(NewNid("b"), NewNid("a"), 5)
+	triple := func(ws []float64) float64 { return ws[0] * 3 }
+	if _, err := vg.CollapseMultiEdges(triple); !errors.Is(err, errTooLarge) {
+		t.Errorf("CollapseMultiEdges over validator bound = %v, want errTooLarge", err)
+	}
+}
+
+func TestReachableSubgraph(t *testing.T) {
+	// a -> b -> c, d -> b, e -> f
+	g := buildGraph(
+		[]string{"a", "b", "c", "d", "e", "f"},
+		[][2]string{{"a", "b"}, {"b", "c"}, {"d", "b"}, {"e", "f"}},
+		1, 2, 3, 4,
+	)
+
+	sub, err := g.ReachableSubgraph([]ID{NewNid("a"), NewNid("e")})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if sub.GetNodeCount() != 5 {
+		t.Errorf("node count = %d, want 5", sub.GetNodeCount())
+	}
+	if _, ok := sub.LookupNode(NewNid("d")); ok {
+		t.Error("d is not reachable but was included")
+	}
+	if w, _ := sub.GetWeight(NewNid("c"), NewNid("b")); w != 2 {
+		t.Errorf("edge b -> c = %v, want 2", w)
+	}
+	if w, _ := sub.GetWeight(NewNid("f"), NewNid("e")); w != 4 {
+		t.Errorf("edge e -> f = %v, want 4", w)
+	}
+
+	if _, err := g.ReachableSubgraph([]ID{NewNid("x")}); err == nil {
+		t.Error("expected error for missing root")
+	}
+}
+
+func TestSpanningForest(t *testing.T) {
+	g := buildGraph(
+		[]string{"a", "b", "c", "d", "e", "f"},
+		[][2]string{{"a", "b"}, {"b", "a"}, {"c", "a"}, {"b", "c"}, {"e", "d"}, {"f", "f"}},
+		1, 4, 2, 3, 5, 6,
+	)
+
+	forest, roots := g.SpanningForest()
+	if got := strings.Join(idStrings(roots), " "); got != "a d f" {
+		t.Errorf("roots = %s, want a d f", got)
+	}
+	if forest.GetNodeCount() != 6 {
+		t.Errorf("node count = %d, want 6", forest.GetNodeCount())
+	}
+
+	want := []Edge{{NewNid("a"), NewNid("b"), 1}, {NewNid("c"), NewNid("a"), 2}, {NewNid("e"), NewNid("d"), 5}}
+	if got := forest.EdgesSortedByWeight(false); !reflect.DeepEqual(got, want) {
+		t.Errorf("forest edges = %v, want %v", got, want)
+	}
+	if !forest.IsDAG() {
+		t.Error("forest should be acyclic")
+	}
+}
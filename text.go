@@ -0,0 +1,53 @@
+package kraph
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// 格式化权重，整数也保留一位小数，例如 1.0
+func formatWeight(wgt float64) string {
+	s := strconv.FormatFloat(wgt, 'f', -1, 64)
+	if !strings.ContainsAny(s, ".IN") {
+		s += ".0"
+	}
+
+	return s
+}
+
+func (g *graph) WriteAdjacencyList(w io.Writer) error {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	bw := bufio.NewWriter(w)
+	for _, id := range g.unsafeNodeIDs() {
+		bw.WriteString(id.String())
+
+		targets := make([]ID, 0, len(g.nodeTargets[id]))
+		for tid := range g.nodeTargets[id] {
+			targets = append(targets, tid)
+		}
+		sortIDs(targets)
+
+		for i, tid := range targets {
+			if i == 0 {
+				bw.WriteString(" -> ")
+			} else {
+				bw.WriteString(", ")
+			}
+			bw.WriteString(tid.String() + "(" + formatWeight(g.nodeTargets[id][tid]) + ")")
+		}
+		bw.WriteByte('\n')
+	}
+
+	return bw.Flush()
+}
+
+func (g *graph) String() string {
+	var b strings.Builder
+	g.WriteAdjacencyList(&b)
+
+	return b.String()
+}
@@ -0,0 +1,19 @@
+package kraph
+
+import "testing"
+
+func TestWriteAdjacencyList(t *testing.T) {
+	g := buildGraph(
+		[]string{"c", "a", "b", "d"},
+		[][2]string{{"a", "c"}, {"a", "b"}, {"b", "c"}},
+		2.5, 1.0, 0.25,
+	)
+
+	want := "a -> b(1.0), c(2.5)\n" +
+		"b -> c(0.25)\n" +
+		"c\n" +
+		"d\n"
+	if got := g.String(); got != want {
+		t.Errorf("String() =\n%s\nwant\n%s", got, want)
+	}
+}
@@ -0,0 +1,226 @@
+package kraph
+
+import (
+	"container/heap"
+	"errors"
+	"fmt"
+)
+
+func (g *graph) MultiSourceDistances(sources []ID) (map[ID]int, error) {
+	sources = g.cfg.keys(sources)
+
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	for _, id := range sources {
+		if !g.unsafeIdExist(id) {
+			return nil, fmt.Errorf("%s does not exist in graph", id)
+		}
+	}
+
+	return g.unsafeBFS(sources), nil
+}
+
+// 以 sources 为起点沿下游方向做 BFS，返回每个可达 node 的跳数
+func (g *graph) unsafeBFS(sources []ID) map[ID]int {
+	dist := make(map[ID]int, len(sources))
+	queue := make([]ID, 0, len(sources))
+	for _, id := range sources {
+		if _, ok := dist[id]; !ok {
+			dist[id] = 0
+			queue = append(queue, id)
+		}
+	}
+
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+
+		for tid := range g.nodeTargets[cur] {
+			if _, ok := dist[tid]; !ok {
+				dist[tid] = dist[cur] + 1
+				queue = append(queue, tid)
+			}
+		}
+	}
+
+	return dist
+}
+
+func (g *graph) unsafeEccentricity(id ID) (int, error) {
+	dist := g.unsafeBFS([]ID{id})
+	if len(dist) < len(g.nodeList) {
+		for nid := range g.nodeList {
+			if _, ok := dist[nid]; !ok {
+				return 0, fmt.Errorf("%w: %s cannot reach %s", ErrUnreachable, id, nid)
+			}
+		}
+	}
+
+	ecc := 0
+	for _, d := range dist {
+		if d > ecc {
+			ecc = d
+		}
+	}
+
+	return ecc, nil
+}
+
+func (g *graph) Eccentricity(id ID) (int, error) {
+	id = g.cfg.key(id)
+
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	if !g.unsafeIdExist(id) {
+		return 0, fmt.Errorf("%s does not exist in graph", id)
+	}
+
+	return g.unsafeEccentricity(id)
+}
+
+func (g *graph) Diameter() (int, error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	eccs, err := g.unsafeEccentricities()
+	if err != nil {
+		return 0, err
+	}
+
+	diameter := 0
+	for _, ecc := range eccs {
+		if ecc > diameter {
+			diameter = ecc
+		}
+	}
+
+	return diameter, nil
+}
+
+// 返回所有 node 的离心率，任意一个 node 的离心率为无穷大时返回 error
+func (g *graph) unsafeEccentricities() (map[ID]int, error) {
+	if len(g.nodeList) == 0 {
+		return nil, errors.New("graph is empty")
+	}
+
+	eccs := make(map[ID]int, len(g.nodeList))
+	for id := range g.nodeList {
+		ecc, err := g.unsafeEccentricity(id)
+		if err != nil {
+			return nil, err
+		}
+		eccs[id] = ecc
+	}
+
+	return eccs, nil
+}
+
+func (g *graph) Radius() (int, error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	eccs, err := g.unsafeEccentricities()
+	if err != nil {
+		return 0, err
+	}
+
+	return minEccentricity(eccs), nil
+}
+
+func (g *graph) Center() []ID {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	eccs, err := g.unsafeEccentricities()
+	if err != nil {
+		return nil
+	}
+
+	radius := minEccentricity(eccs)
+	center := make([]ID, 0)
+	for id, ecc := range eccs {
+		if ecc == radius {
+			center = append(center, id)
+		}
+	}
+	sortIDs(center)
+
+	return center
+}
+
+func minEccentricity(eccs map[ID]int) int {
+	first := true
+	radius := 0
+	for _, ecc := range eccs {
+		if first || ecc < radius {
+			radius = ecc
+			first = false
+		}
+	}
+
+	return radius
+}
+
+// 按权重排列的边的最小堆，权重相同时按 (From, To) 的字符串顺序
+type edgeHeap []Edge
+
+func (h edgeHeap) Len() int { return len(h) }
+func (h edgeHeap) Less(i, j int) bool {
+	if h[i].Weight != h[j].Weight {
+		return h[i].Weight < h[j].Weight
+	}
+	if fi, fj := h[i].From.String(), h[j].From.String(); fi != fj {
+		return fi < fj
+	}
+
+	return h[i].To.String() < h[j].To.String()
+}
+func (h edgeHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *edgeHeap) Push(x interface{}) { *h = append(*h, x.(Edge)) }
+func (h *edgeHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	e := old[n-1]
+	*h = old[:n-1]
+
+	return e
+}
+
+func (g *graph) WalkByWeight(start ID, visit func(from, to ID, w float64) bool) error {
+	start = g.cfg.key(start)
+
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	if !g.unsafeIdExist(start) {
+		return fmt.Errorf("%s does not exist in graph", start)
+	}
+
+	seen := map[ID]bool{start: true}
+	h := &edgeHeap{}
+	push := func(from ID) {
+		for to, wgt := range g.nodeTargets[from] {
+			if !seen[to] {
+				heap.Push(h, Edge{From: from, To: to, Weight: wgt})
+			}
+		}
+	}
+
+	push(start)
+	for h.Len() > 0 {
+		e := heap.Pop(h).(Edge)
+		if seen[e.To] {
+			continue
+		}
+		seen[e.To] = true
+
+		if !visit(e.From, e.To, e.Weight) {
+			break
+		}
+		push(e.To)
+	}
+
+	return nil
+}
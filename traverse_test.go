@@ -0,0 +1,125 @@
+package kraph
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestMultiSourceDistances(t *testing.T) {
+	// a -> b -> c -> d, e -> d, f 孤立
+	g := buildGraph(
+		[]string{"a", "b", "c", "d", "e", "f"},
+		[][2]string{{"a", "b"}, {"b", "c"}, {"c", "d"}, {"e", "d"}},
+	)
+
+	dist, err := g.MultiSourceDistances([]ID{NewNid("a"), NewNid("e")})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[ID]int{
+		NewNid("a"): 0,
+		NewNid("b"): 1,
+		NewNid("c"): 2,
+		NewNid("d"): 1,
+		NewNid("e"): 0,
+	}
+	if !reflect.DeepEqual(dist, want) {
+		t.Errorf("MultiSourceDistances = %v, want %v", dist, want)
+	}
+
+	if _, err := g.MultiSourceDistances([]ID{NewNid("missing")}); err == nil {
+		t.Error("expected error for missing source")
+	}
+}
+
+func TestEccentricityDiameter(t *testing.T) {
+	// 环 a -> b -> c -> d -> a，外加捷径 a -> c
+	g := buildGraph(
+		[]string{"a", "b", "c", "d"},
+		[][2]string{{"a", "b"}, {"b", "c"}, {"c", "d"}, {"d", "a"}, {"a", "c"}},
+	)
+
+	cases := map[string]int{"a": 2, "b": 3, "c": 3, "d": 2}
+	for id, want := range cases {
+		if ecc, err := g.Eccentricity(NewNid(id)); err != nil || ecc != want {
+			t.Errorf("Eccentricity(%s) = %v, %v; want %v", id, ecc, err, want)
+		}
+	}
+
+	if d, err := g.Diameter(); err != nil || d != 3 {
+		t.Errorf("Diameter() = %v, %v; want 3", d, err)
+	}
+
+	g.AddNode(NewNode(NewNid("e")))
+	if _, err := g.Eccentricity(NewNid("a")); !errors.Is(err, ErrUnreachable) {
+		t.Errorf("expected ErrUnreachable, got %v", err)
+	}
+	if _, err := g.Diameter(); !errors.Is(err, ErrUnreachable) {
+		t.Errorf("expected ErrUnreachable, got %v", err)
+	}
+
+	if _, err := NewGraph().Diameter(); err == nil {
+		t.Error("Diameter of empty graph should return error")
+	}
+}
+
+func TestRadiusCenter(t *testing.T) {
+	g := buildGraph(
+		[]string{"a", "b", "c", "d"},
+		[][2]string{{"a", "b"}, {"b", "c"}, {"c", "d"}, {"d", "a"}, {"a", "c"}},
+	)
+
+	if r, err := g.Radius(); err != nil || r != 2 {
+		t.Errorf("Radius() = %v, %v; want 2", r, err)
+	}
+	if c := idStrings(g.Center()); !reflect.DeepEqual(c, []string{"a", "d"}) {
+		t.Errorf("Center() = %v", c)
+	}
+
+	g.AddNode(NewNode(NewNid("e")))
+	if _, err := g.Radius(); !errors.Is(err, ErrUnreachable) {
+		t.Errorf("expected ErrUnreachable, got %v", err)
+	}
+	if c := g.Center(); c != nil {
+		t.Errorf("Center() of disconnected graph = %v", c)
+	}
+}
+
+func TestWalkByWeight(t *testing.T) {
+	// a -> b(4), a -> c(1), c -> b(2), b -> d(1), c -> d(5), e 不可达
+	g := buildGraph(
+		[]string{"a", "b", "c", "d", "e"},
+		[][2]string{{"a", "b"}, {"a", "c"}, {"c", "b"}, {"b", "d"}, {"c", "d"}},
+		4, 1, 2, 1, 5,
+	)
+
+	got := make([]string, 0)
+	err := g.WalkByWeight(NewNid("a"), func(from, to ID, w float64) bool {
+		got = append(got, fmt.Sprintf("%s->%s(%v)", from, to, w))
+		return true
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"a->c(1)", "c->b(2)", "b->d(1)"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("WalkByWeight = %v, want %v", got, want)
+	}
+
+	count := 0
+	g.WalkByWeight(NewNid("a"), func(from, to ID, w float64) bool {
+		count++
+		return false
+	})
+	if count != 1 {
+		t.Errorf("visit called %d times after returning false", count)
+	}
+
+	if err := g.WalkByWeight(NewNid("x"), func(from, to ID, w float64) bool { return true }); err == nil {
+		t.Error("expected error for missing start")
+	}
+}
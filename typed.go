@@ -0,0 +1,205 @@
+package kraph
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/pquerna/ffjson/ffjson"
+)
+
+// TypedGraph 在 Graph 的基础上为 node 附加类型为 N 的值，为边附加类型为 E 的值
+// 删除 node 或边时对应的值会一起被删除，Graph 的其它方法（如 FilterEdges、Union、ReverseEdge）产生的新边不带值，TransposeInPlace 会保留边上的值
+type TypedGraph[N, E any] struct {
+	*graph
+}
+
+// NewTypedGraph 创建一个空的 TypedGraph，opts 与 NewGraph 相同
+func NewTypedGraph[N, E any](opts ...Option) *TypedGraph[N, E] {
+	return &TypedGraph[N, E]{graph: NewGraph(opts...).(*graph)}
+}
+
+// 携带值的 node，GetNodeValue 通过类型断言取出 val
+type typedNode[N any] struct {
+	id  ID
+	val N
+}
+
+func (n *typedNode[N]) GetId() ID {
+	return n.id
+}
+
+// AddNodeValue 添加一个携带 val 的 node，规则与 AddNode 相同，node 已经存在或达到容量上限时返回 false
+func (t *TypedGraph[N, E]) AddNodeValue(id ID, val N) bool {
+	return t.AddNode(&typedNode[N]{id: id, val: val})
+}
+
+// GetNodeValue 返回 node 携带的值，node 不存在或不是通过 AddNodeValue 添加的时候返回 false
+func (t *TypedGraph[N, E]) GetNodeValue(id ID) (N, bool) {
+	var zero N
+	nd, ok := t.LookupNode(id)
+	if !ok {
+		return zero, false
+	}
+
+	tn, ok := nd.(*typedNode[N])
+	if !ok {
+		return zero, false
+	}
+
+	return tn.val, true
+}
+
+// AddEdgeValue 按照 AddEdge 的规则添加 from -> to 的边并为其附加 val，边已经存在时合并权重并覆盖原来的值
+func (t *TypedGraph[N, E]) AddEdgeValue(from, to ID, w float64, val E) error {
+	g := t.graph
+	from, to = g.cfg.key(from), g.cfg.key(to)
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if err := g.unsafeCheckFrozen(); err != nil {
+		return err
+	}
+
+	if err := g.unsafeAddEdgeChecked(from, to, w); err != nil {
+		return err
+	}
+
+	if g.edgeVals == nil {
+		g.edgeVals = make(map[edgeKey]interface{})
+	}
+	g.edgeVals[edgeKey{from, to}] = val
+
+	return nil
+}
+
+// GetEdgeValue 返回 from -> to 的边携带的值，边不存在或没有值时返回 false
+func (t *TypedGraph[N, E]) GetEdgeValue(from, to ID) (E, bool) {
+	g := t.graph
+	from, to = g.cfg.key(from), g.cfg.key(to)
+
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	v, ok := g.edgeVals[edgeKey{from, to}]
+	if !ok {
+		var zero E
+		return zero, false
+	}
+
+	// E 为接口类型时保存的值可能是 nil
+	val, _ := v.(E)
+
+	return val, true
+}
+
+type jsonValueNode struct {
+	ID    string          `json:"id"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+type jsonValueEdge struct {
+	From   string          `json:"from"`
+	To     string          `json:"to"`
+	Weight float64         `json:"weight"`
+	Value  json.RawMessage `json:"value,omitempty"`
+}
+
+type jsonValueDoc struct {
+	Nodes []jsonValueNode `json:"nodes"`
+	Edges []jsonValueEdge `json:"edges"`
+}
+
+// MarshalJSONWithValues 以 {"nodes":[{"id","value"}],"edges":[{"from","to","weight","value"}]} 的格式输出整个图，
+// node 和边按 id 排序，携带的值通过 encodeValue 编码，没有值的 node 或边不输出 value 字段
+// 只在复制图的内容时持有读锁，encodeValue 在锁外调用，它返回的 error 会直接返回
+func (t *TypedGraph[N, E]) MarshalJSONWithValues(encodeValue func(any) (json.RawMessage, error)) ([]byte, error) {
+	g := t.graph
+
+	g.mu.RLock()
+	ids := g.unsafeNodeIDs()
+	nodes := make([]Node, len(ids))
+	for i, id := range ids {
+		nodes[i] = g.nodeList[id]
+	}
+	edges := g.unsafeEdges()
+	vals := make([]interface{}, len(edges))
+	has := make([]bool, len(edges))
+	for i, e := range edges {
+		vals[i], has[i] = g.edgeVals[edgeKey{e.From, e.To}]
+	}
+	g.mu.RUnlock()
+
+	doc := jsonValueDoc{
+		Nodes: make([]jsonValueNode, 0, len(nodes)),
+		Edges: make([]jsonValueEdge, 0, len(edges)),
+	}
+	for i, nd := range nodes {
+		n := jsonValueNode{ID: ids[i].String()}
+		if tn, ok := nd.(*typedNode[N]); ok {
+			raw, err := encodeValue(tn.val)
+			if err != nil {
+				return nil, fmt.Errorf("node %s: %w", ids[i], err)
+			}
+			n.Value = raw
+		}
+		doc.Nodes = append(doc.Nodes, n)
+	}
+
+	for i, e := range edges {
+		je := jsonValueEdge{From: e.From.String(), To: e.To.String(), Weight: e.Weight}
+		if has[i] {
+			raw, err := encodeValue(vals[i])
+			if err != nil {
+				return nil, fmt.Errorf("edge %s -> %s: %w", e.From, e.To, err)
+			}
+			je.Value = raw
+		}
+		doc.Edges = append(doc.Edges, je)
+	}
+
+	return ffjson.Marshal(doc)
+}
+
+// LoadJSONWithValues 读取 MarshalJSONWithValues 的输出并构建 TypedGraph，带有 value 字段的 node 和边分别通过
+// decodeNode 和 decodeEdge 还原其携带的值，对应的函数为 nil 时忽略这些值，edges 中引用的 node 必须出现在 nodes 中
+func LoadJSONWithValues[N, E any](data []byte, decodeNode func(json.RawMessage) (N, error), decodeEdge func(json.RawMessage) (E, error), opts ...Option) (*TypedGraph[N, E], error) {
+	var doc jsonValueDoc
+	if err := ffjson.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+
+	t := NewTypedGraph[N, E](opts...)
+	for _, n := range doc.Nodes {
+		id := NewNid(n.ID)
+		if len(n.Value) == 0 || decodeNode == nil {
+			t.AddNode(NewNode(id))
+			continue
+		}
+
+		val, err := decodeNode(n.Value)
+		if err != nil {
+			return nil, fmt.Errorf("node %s: %w", n.ID, err)
+		}
+		t.AddNodeValue(id, val)
+	}
+
+	for _, e := range doc.Edges {
+		from, to := NewNid(e.From), NewNid(e.To)
+		if len(e.Value) == 0 || decodeEdge == nil {
+			if err := t.AddEdge(to, from, e.Weight); err != nil {
+				return nil, fmt.Errorf("edge %s -> %s: %w", e.From, e.To, err)
+			}
+			continue
+		}
+
+		val, err := decodeEdge(e.Value)
+		if err != nil {
+			return nil, fmt.Errorf("edge %s -> %s: %w", e.From, e.To, err)
+		}
+		if err := t.AddEdgeValue(from, to, e.Weight, val); err != nil {
+			return nil, fmt.Errorf("edge %s -> %s: %w", e.From, e.To, err)
+		}
+	}
+
+	return t, nil
+}
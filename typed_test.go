@@ -0,0 +1,126 @@
+package kraph
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+type relation struct {
+	kind string
+	at   time.Time
+}
+
+func TestTypedGraphEdgeValue(t *testing.T) {
+	g := NewTypedGraph[string, relation]()
+	a, b, c := NewNid("a"), NewNid("b"), NewNid("c")
+	g.AddNodeValue(a, "alice")
+	g.AddNodeValue(b, "bob")
+	g.AddNode(NewNode(c))
+
+	if v, ok := g.GetNodeValue(a); !ok || v != "alice" {
+		t.Errorf("GetNodeValue(a) = %q, %v", v, ok)
+	}
+	if _, ok := g.GetNodeValue(c); ok {
+		t.Error("plain node should have no value")
+	}
+
+	at := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	if err := g.AddEdgeValue(a, b, 2, relation{"follows", at}); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.AddEdgeValue(a, NewNid("x"), 1, relation{}); err == nil {
+		t.Error("expected error for missing node")
+	}
+
+	if v, ok := g.GetEdgeValue(a, b); !ok || v.kind != "follows" || !v.at.Equal(at) {
+		t.Errorf("GetEdgeValue(a, b) = %v, %v", v, ok)
+	}
+	if w, _ := g.EdgeWeight(a, b); w != 2 {
+		t.Errorf("weight = %v, want 2", w)
+	}
+	if _, ok := g.GetEdgeValue(b, a); ok {
+		t.Error("reverse edge should have no value")
+	}
+
+	// 删除边后再次添加的同一条边不应该带有旧值
+	if err := g.DeleteEdge(b, a); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := g.GetEdgeValue(a, b); ok {
+		t.Error("value should be dropped with the edge")
+	}
+	g.AddEdge(b, a, 1)
+	if _, ok := g.GetEdgeValue(a, b); ok {
+		t.Error("re-added edge should have no value")
+	}
+
+	g.AddEdgeValue(b, c, 1, relation{kind: "knows"})
+	g.DeleteNode(c)
+	g.AddNode(NewNode(c))
+	g.AddEdge(c, b, 1)
+	if _, ok := g.GetEdgeValue(b, c); ok {
+		t.Error("value should be dropped with the node")
+	}
+}
+
+func TestTypedGraphJSONWithValues(t *testing.T) {
+	g := NewTypedGraph[int, string]()
+	a, b, c := NewNid("a"), NewNid("b"), NewNid("c")
+	g.AddNodeValue(a, 1)
+	g.AddNodeValue(b, 2)
+	g.AddNode(NewNode(c))
+	g.AddEdgeValue(a, b, 1.5, "owns")
+	g.AddEdge(c, b, 2) // b -> c 不带值
+
+	encode := func(v any) (json.RawMessage, error) {
+		return json.Marshal(v)
+	}
+	data, err := g.MarshalJSONWithValues(encode)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `{"nodes":[{"id":"a","value":1},{"id":"b","value":2},{"id":"c"}],` +
+		`"edges":[{"from":"a","to":"b","weight":1.5,"value":"owns"},{"from":"b","to":"c","weight":2}]}`
+	if string(data) != want {
+		t.Errorf("MarshalJSONWithValues() = %s, want %s", data, want)
+	}
+
+	decodeNode := func(raw json.RawMessage) (int, error) {
+		var v int
+		err := json.Unmarshal(raw, &v)
+		return v, err
+	}
+	decodeEdge := func(raw json.RawMessage) (string, error) {
+		var v string
+		err := json.Unmarshal(raw, &v)
+		return v, err
+	}
+	loaded, err := LoadJSONWithValues(data, decodeNode, decodeEdge)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if v, ok := loaded.GetNodeValue(b); !ok || v != 2 {
+		t.Errorf("GetNodeValue(b) = %v, %v; want 2", v, ok)
+	}
+	if _, ok := loaded.GetNodeValue(c); ok {
+		t.Error("c should have no value")
+	}
+	if v, ok := loaded.GetEdgeValue(a, b); !ok || v != "owns" {
+		t.Errorf("GetEdgeValue(a, b) = %q, %v; want owns", v, ok)
+	}
+	if w, err := loaded.EdgeWeight(b, c); err != nil || w != 2 {
+		t.Errorf("EdgeWeight(b, c) = %v, %v; want 2", w, err)
+	}
+
+	errBad := errors.New("bad value")
+	if _, err := g.MarshalJSONWithValues(func(any) (json.RawMessage, error) { return nil, errBad }); !errors.Is(err, errBad) {
+		t.Errorf("expected encoder error, got %v", err)
+	}
+	if _, err := LoadJSONWithValues[int, string]([]byte(`{"nodes":[{"id":"a"}],"edges":[{"from":"a","to":"x","weight":1}]}`), nil, nil); err == nil {
+		t.Error("expected error for edge to missing node")
+	}
+}
@@ -0,0 +1,176 @@
+package kraph
+
+import "sort"
+
+// 以整数下标表示元素的并查集，使用路径压缩和按大小合并
+type unionFind struct {
+	parent []int
+	size   []int
+}
+
+func newUnionFind(n int) *unionFind {
+	uf := &unionFind{
+		parent: make([]int, n),
+		size:   make([]int, n),
+	}
+	for i := range uf.parent {
+		uf.parent[i] = i
+		uf.size[i] = 1
+	}
+
+	return uf
+}
+
+// 添加一个新的单元素集合，返回它的下标
+func (uf *unionFind) add() int {
+	uf.parent = append(uf.parent, len(uf.parent))
+	uf.size = append(uf.size, 1)
+
+	return len(uf.parent) - 1
+}
+
+func (uf *unionFind) find(x int) int {
+	root := x
+	for uf.parent[root] != root {
+		root = uf.parent[root]
+	}
+
+	for uf.parent[x] != root {
+		uf.parent[x], x = root, uf.parent[x]
+	}
+
+	return root
+}
+
+// 合并 x 和 y 所在的集合，如果两者已经在同一个集合中则返回 false
+func (uf *unionFind) union(x, y int) bool {
+	rx, ry := uf.find(x), uf.find(y)
+	if rx == ry {
+		return false
+	}
+
+	if uf.size[rx] < uf.size[ry] {
+		rx, ry = ry, rx
+	}
+	uf.parent[ry] = rx
+	uf.size[rx] += uf.size[ry]
+
+	return true
+}
+
+// WithIncrementalConnectivity 时随边的添加增量维护的弱连通分量
+// 添加 node 和边时直接更新并查集，删除会使其失效，在下一次 Connected 时重新构建
+type connectivity struct {
+	uf    *unionFind
+	index map[ID]int
+	stale bool
+}
+
+func newConnectivity() *connectivity {
+	return &connectivity{
+		uf:    newUnionFind(0),
+		index: make(map[ID]int),
+	}
+}
+
+func (c *connectivity) addNode(id ID) {
+	if !c.stale {
+		c.index[id] = c.uf.add()
+	}
+}
+
+func (c *connectivity) addEdge(from, to ID) {
+	if !c.stale {
+		c.uf.union(c.index[from], c.index[to])
+	}
+}
+
+// 根据 g 当前的 node 和边重新构建并查集，调用方需要持有 g 的锁
+func (c *connectivity) rebuild(g *graph) {
+	c.uf = newUnionFind(0)
+	c.index = make(map[ID]int, len(g.nodeList))
+	c.stale = false
+	for id := range g.nodeList {
+		c.addNode(id)
+	}
+
+	for from, tmap := range g.nodeTargets {
+		for to := range tmap {
+			c.addEdge(from, to)
+		}
+	}
+}
+
+func (g *graph) Connected(a, b ID) bool {
+	a, b = g.cfg.key(a), g.cfg.key(b)
+
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	if !g.unsafeIdExist(a) || !g.unsafeIdExist(b) {
+		return false
+	}
+
+	if g.conn == nil {
+		_, ok := g.unsafeUndirectedBFS(a)[b]
+		return ok
+	}
+
+	// find 会压缩路径，多个读者同时查询时需要单独加锁
+	g.connMu.Lock()
+	defer g.connMu.Unlock()
+
+	if g.conn.stale {
+		g.conn.rebuild(g)
+	}
+
+	return g.conn.uf.find(g.conn.index[a]) == g.conn.uf.find(g.conn.index[b])
+}
+
+// 忽略边的方向从 start 开始 BFS，返回所有可达的 node
+func (g *graph) unsafeUndirectedBFS(start ID) map[ID]bool {
+	seen := map[ID]bool{start: true}
+	queue := []ID{start}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+
+		for _, adj := range []map[ID]float64{g.nodeTargets[cur], g.nodeSources[cur]} {
+			for nid := range adj {
+				if !seen[nid] {
+					seen[nid] = true
+					queue = append(queue, nid)
+				}
+			}
+		}
+	}
+
+	return seen
+}
+
+func (g *graph) ComponentSizes() []int {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	index := make(map[ID]int, len(g.nodeList))
+	for id := range g.nodeList {
+		index[id] = len(index)
+	}
+
+	uf := newUnionFind(len(index))
+	for from, tmap := range g.nodeTargets {
+		for to := range tmap {
+			uf.union(index[from], index[to])
+		}
+	}
+
+	sizes := make([]int, 0)
+	for i := range uf.parent {
+		if uf.find(i) == i {
+			sizes = append(sizes, uf.size[i])
+		}
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(sizes)))
+
+	return sizes
+}
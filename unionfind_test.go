@@ -0,0 +1,79 @@
+package kraph
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestComponentSizes(t *testing.T) {
+	// {a, b, c} 通过不同方向的边弱连通，{d, e}，{f}，{g} 只有自环
+	g := buildGraph(
+		[]string{"a", "b", "c", "d", "e", "f", "g"},
+		[][2]string{{"a", "b"}, {"c", "b"}, {"d", "e"}, {"g", "g"}},
+	)
+
+	if got := g.ComponentSizes(); !reflect.DeepEqual(got, []int{3, 2, 1, 1}) {
+		t.Errorf("ComponentSizes() = %v, want [3 2 1 1]", got)
+	}
+
+	if got := NewGraph().ComponentSizes(); len(got) != 0 {
+		t.Errorf("empty graph ComponentSizes() = %v", got)
+	}
+}
+
+func TestUnionFind(t *testing.T) {
+	uf := newUnionFind(5)
+	if !uf.union(0, 1) || !uf.union(3, 4) || !uf.union(1, 4) {
+		t.Fatal("union of disjoint sets returned false")
+	}
+	if uf.union(0, 3) {
+		t.Error("union of joined sets returned true")
+	}
+	if uf.find(0) != uf.find(4) || uf.find(2) == uf.find(0) {
+		t.Error("find returned wrong roots")
+	}
+	if uf.size[uf.find(0)] != 4 {
+		t.Errorf("size = %d, want 4", uf.size[uf.find(0)])
+	}
+}
+
+func TestConnected(t *testing.T) {
+	a, b, c, d := NewNid("a"), NewNid("b"), NewNid("c"), NewNid("d")
+
+	for _, g := range []Graph{NewGraph(), NewGraph(WithIncrementalConnectivity())} {
+		for _, id := range []ID{a, b, c, d} {
+			g.AddNode(NewNode(id))
+		}
+
+		check := func(x, y ID, want bool) {
+			t.Helper()
+			if got := g.Connected(x, y); got != want {
+				t.Errorf("Connected(%s, %s) = %v, want %v", x, y, got, want)
+			}
+		}
+
+		g.AddEdge(a, b, 1) // b -> a
+		g.AddEdge(b, c, 1) // c -> b
+		check(a, c, true)
+		check(c, a, true)
+		check(a, d, false)
+		check(d, d, true)
+		check(a, NewNid("x"), false)
+
+		// 删除边后需要重新计算
+		g.DeleteEdge(b, c)
+		check(a, c, false)
+		check(a, b, true)
+
+		g.AddEdge(d, c, 1)
+		g.AddEdge(a, d, 1)
+		check(b, c, true)
+
+		g.DeleteNode(d)
+		check(b, c, false)
+		g.AddNode(NewNode(d))
+		check(d, a, false)
+		g.AddEdge(d, c, 1)
+		check(c, d, true)
+	}
+}
@@ -0,0 +1,138 @@
+package kraph
+
+import (
+	"fmt"
+	"math"
+)
+
+func (g *graph) NormalizeOutWeights() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.unsafeCheckFrozen() != nil {
+		return
+	}
+
+	for from, tmap := range g.nodeTargets {
+		for to, wgt := range g.normalizedWeights(tmap) {
+			g.unsafeSetEdge(from, to, wgt)
+		}
+	}
+}
+
+func (g *graph) NormalizeInWeights() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.unsafeCheckFrozen() != nil {
+		return
+	}
+
+	for to, smap := range g.nodeSources {
+		for from, wgt := range g.normalizedWeights(smap) {
+			g.unsafeSetEdge(from, to, wgt)
+		}
+	}
+}
+
+// normalizedWeights 返回 wmap 中每个权重除以权重之和后的结果，权重和为 0 或非有限值，
+// 或者任意一个结果无法通过权重校验时返回 nil，对应的 node 保持不变
+func (g *graph) normalizedWeights(wmap map[ID]float64) map[ID]float64 {
+	sum := 0.0
+	for _, wgt := range wmap {
+		sum += wgt
+	}
+
+	if sum == 0 || math.IsNaN(sum) || math.IsInf(sum, 0) {
+		return nil
+	}
+
+	res := make(map[ID]float64, len(wmap))
+	for id, wgt := range wmap {
+		nw := wgt / sum
+		if g.cfg.validateWeight(nw) != nil {
+			return nil
+		}
+		res[id] = nw
+	}
+
+	return res
+}
+
+func (g *graph) ScaleWeights(f func(float64) float64) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if err := g.unsafeCheckFrozen(); err != nil {
+		return err
+	}
+
+	// 先计算并校验所有新权重，避免校验失败时图处于修改了一半的状态
+	scaled := make([]Edge, 0, g.st.edges)
+	for from, tmap := range g.nodeTargets {
+		for to, wgt := range tmap {
+			nw := f(wgt)
+			if err := g.cfg.validateWeight(nw); err != nil {
+				return fmt.Errorf("edge %s -> %s: %w", from, to, err)
+			}
+			scaled = append(scaled, Edge{From: from, To: to, Weight: nw})
+		}
+	}
+
+	for _, e := range scaled {
+		g.unsafeSetEdge(e.From, e.To, e.Weight)
+	}
+
+	return nil
+}
+
+func (g *graph) WeightHistogram(bins int) ([]int, float64, float64) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	if bins < 1 || g.st.edges == 0 {
+		return nil, 0, 0
+	}
+
+	// 只统计有限的权重，WithNonFiniteWeights 允许的 NaN 和无穷大会使区间宽度失去意义
+	lo, hi := math.Inf(1), math.Inf(-1)
+	finite := 0
+	for _, tmap := range g.nodeTargets {
+		for _, wgt := range tmap {
+			if math.IsNaN(wgt) || math.IsInf(wgt, 0) {
+				continue
+			}
+			lo = math.Min(lo, wgt)
+			hi = math.Max(hi, wgt)
+			finite++
+		}
+	}
+
+	if finite == 0 {
+		return nil, 0, 0
+	}
+
+	counts := make([]int, bins)
+	width := (hi - lo) / float64(bins)
+	for _, tmap := range g.nodeTargets {
+		for _, wgt := range tmap {
+			if math.IsNaN(wgt) || math.IsInf(wgt, 0) {
+				continue
+			}
+
+			i := 0
+			if width > 0 {
+				i = int((wgt - lo) / width)
+			}
+			if i < 0 {
+				i = 0
+			}
+			if i >= bins {
+				i = bins - 1
+			}
+			counts[i]++
+		}
+	}
+
+	return counts, lo, hi
+}
@@ -0,0 +1,181 @@
+package kraph
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestNormalizeWeights(t *testing.T) {
+	newTestGraph := func() Graph {
+		return buildGraph(
+			[]string{"a", "b", "c"},
+			[][2]string{{"a", "b"}, {"a", "c"}, {"b", "c"}},
+			1.0, 3.0, 2.0,
+		)
+	}
+	a, b, c := NewNid("a"), NewNid("b"), NewNid("c")
+
+	g := newTestGraph()
+	g.NormalizeOutWeights()
+	cases := []struct {
+		from, to ID
+		wgt      float64
+	}{
+		{a, b, 0.25},
+		{a, c, 0.75},
+		{b, c, 1.0},
+	}
+	for _, cs := range cases {
+		if w, _ := g.GetWeight(cs.to, cs.from); w != cs.wgt {
+			t.Errorf("out-normalized %s -> %s = %v, want %v", cs.from, cs.to, w, cs.wgt)
+		}
+	}
+	// 两个方向的索引需要保持一致
+	if tmap, _ := g.GetTargets(a); len(tmap) != 2 {
+		t.Errorf("targets of a = %v", tmap)
+	}
+
+	g = newTestGraph()
+	g.NormalizeInWeights()
+	cases = []struct {
+		from, to ID
+		wgt      float64
+	}{
+		{a, b, 1.0},
+		{a, c, 0.6},
+		{b, c, 0.4},
+	}
+	for _, cs := range cases {
+		if w, _ := g.GetWeight(cs.to, cs.from); w != cs.wgt {
+			t.Errorf("in-normalized %s -> %s = %v, want %v", cs.from, cs.to, w, cs.wgt)
+		}
+	}
+}
+
+func TestNormalizeWeightsSkipsInvalid(t *testing.T) {
+	a, b, c := NewNid("a"), NewNid("b"), NewNid("c")
+
+	// 权重和为非有限值的 node 保持不变
+	g := NewGraph(WithNonFiniteWeights())
+	for _, id := range []ID{a, b, c} {
+		g.AddNode(NewNode(id))
+	}
+	g.AddEdge(b, a, math.Inf(1))
+	g.AddEdge(c, a, 1)
+	g.NormalizeOutWeights()
+	if w, _ := g.EdgeWeight(a, b); !math.IsInf(w, 1) {
+		t.Errorf("a -> b = %v, want +Inf", w)
+	}
+	if w, _ := g.EdgeWeight(a, c); w != 1 {
+		t.Errorf("a -> c = %v, want 1", w)
+	}
+
+	// 结果无法通过权重校验的 node 保持不变，其余 node 正常归一化
+	g = NewGraph(WithWeightValidator(func(w float64) error {
+		if w < 0.5 {
+			return errors.New("weight too small")
+		}
+		return nil
+	}))
+	for _, id := range []ID{a, b, c} {
+		g.AddNode(NewNode(id))
+	}
+	g.AddEdge(b, a, 1)
+	g.AddEdge(c, a, 3)
+	g.AddEdge(c, b, 2)
+	g.NormalizeOutWeights()
+	cases := []struct {
+		from, to ID
+		wgt      float64
+	}{
+		{a, b, 1},
+		{a, c, 3},
+		{b, c, 1},
+	}
+	for _, cs := range cases {
+		if w, _ := g.EdgeWeight(cs.from, cs.to); w != cs.wgt {
+			t.Errorf("%s -> %s = %v, want %v", cs.from, cs.to, w, cs.wgt)
+		}
+	}
+}
+
+func TestScaleWeights(t *testing.T) {
+	g := buildGraph(
+		[]string{"a", "b", "c"},
+		[][2]string{{"a", "b"}, {"b", "c"}},
+		1000, 250,
+	)
+	a, b, c := NewNid("a"), NewNid("b"), NewNid("c")
+
+	if err := g.ScaleWeights(func(w float64) float64 { return w / 1000 }); err != nil {
+		t.Fatal(err)
+	}
+	if w, _ := g.GetWeight(b, a); w != 1 {
+		t.Errorf("a -> b = %v, want 1", w)
+	}
+	if w, _ := g.GetWeight(c, b); w != 0.25 {
+		t.Errorf("b -> c = %v, want 0.25", w)
+	}
+	if in, _ := g.NeighborsWithWeights(c); in[b] != 0.25 {
+		t.Errorf("sources of c not updated: %v", in)
+	}
+
+	// log(0.25) 有限，log(1) = 0，log(-1) 为 NaN，整个操作被拒绝
+	g.ReplaceEdge(b, a, -1)
+	if err := g.ScaleWeights(math.Log); !errors.Is(err, ErrNonFiniteWeight) {
+		t.Errorf("expected ErrNonFiniteWeight, got %v", err)
+	}
+	if w, _ := g.GetWeight(c, b); w != 0.25 {
+		t.Errorf("b -> c changed to %v after rejected ScaleWeights", w)
+	}
+}
+
+func TestWeightHistogram(t *testing.T) {
+	g := buildGraph(
+		[]string{"a", "b", "c", "d"},
+		[][2]string{{"a", "b"}, {"a", "c"}, {"a", "d"}, {"b", "c"}, {"c", "d"}},
+		0, 1, 2.5, 3.9, 4,
+	)
+
+	counts, min, max := g.WeightHistogram(4)
+	if !reflect.DeepEqual(counts, []int{1, 1, 1, 2}) || min != 0 || max != 4 {
+		t.Errorf("WeightHistogram(4) = %v, %v, %v", counts, min, max)
+	}
+
+	same := buildGraph([]string{"a", "b"}, [][2]string{{"a", "b"}, {"b", "a"}})
+	if counts, _, _ := same.WeightHistogram(3); !reflect.DeepEqual(counts, []int{2, 0, 0}) {
+		t.Errorf("equal weights histogram = %v", counts)
+	}
+
+	if counts, _, _ := NewGraph().WeightHistogram(3); counts != nil {
+		t.Errorf("empty graph histogram = %v, want nil", counts)
+	}
+}
+
+func TestWeightHistogramNonFinite(t *testing.T) {
+	g := NewGraph(WithNonFiniteWeights())
+	a, b, c, d := NewNid("a"), NewNid("b"), NewNid("c"), NewNid("d")
+	for _, id := range []ID{a, b, c, d} {
+		g.AddNode(NewNode(id))
+	}
+	g.AddEdge(b, a, 1)
+	g.AddEdge(c, a, 3)
+	g.AddEdge(d, a, math.Inf(1))
+	g.AddEdge(a, b, math.Inf(-1))
+	g.AddEdge(c, b, math.NaN())
+
+	counts, lo, hi := g.WeightHistogram(2)
+	if !reflect.DeepEqual(counts, []int{1, 1}) || lo != 1 || hi != 3 {
+		t.Errorf("WeightHistogram(2) = %v, %v, %v; want [1 1], 1, 3", counts, lo, hi)
+	}
+
+	only := NewGraph(WithNonFiniteWeights())
+	only.AddNode(NewNode(a))
+	only.AddNode(NewNode(b))
+	only.AddEdge(b, a, math.NaN())
+	if counts, _, _ := only.WeightHistogram(2); counts != nil {
+		t.Errorf("histogram without finite weights = %v, want nil", counts)
+	}
+}